@@ -0,0 +1,154 @@
+// Command ratesim replays a request trace or a synthetic arrival process through a limiter under a simulated
+// clock, and reports how many requests were accepted and rejected, how bursts were handled and how long
+// requests waited.
+//
+// The limiter is built from a limitconfig limit, either from a config file or from flags, so it can be any of
+// token_bucket, leaky_bucket, sliding_window, fixed_window, gcra or a composite of them. leaky_bucket_chan is a
+// queue that leaks in real time and cannot be simulated. Other strategy limiters can be simulated from Go with
+// ratesim.Run.
+//
+//	ratesim -trace requests.csv -algorithm token_bucket -rate 10 -interval 1s -burst 20
+//	ratesim -process bursty -rate-per-second 5 -duration 1h -keys 10 -config limits.yaml -limit api -max-wait 2s
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/limitconfig"
+	"github.com/edfoh/data-structures/pkg/ratesim"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "ratesim:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("ratesim", flag.ContinueOnError)
+
+	trace := fs.String("trace", "", "trace to replay, CSV or JSONL by its extension")
+	process := fs.String("process", "", "synthetic arrivals instead of a trace: poisson, bursty or diurnal")
+	ratePerSecond := fs.Float64("rate-per-second", 1, "average synthetic arrivals per second")
+	duration := fs.Duration("duration", 1*time.Minute, "how long to generate synthetic arrivals for")
+	keys := fs.Int("keys", 1, "how many keys synthetic arrivals are spread over")
+	seed := fs.Int64("seed", 1, "seed for synthetic arrivals")
+	burstEvery := fs.Duration("burst-every", 10*time.Second, "time between bursts of the bursty process")
+	burstSize := fs.Int("burst-size", 10, "arrivals in each burst of the bursty process")
+	period := fs.Duration("period", 24*time.Hour, "period of the diurnal process")
+	amplitude := fs.Float64("amplitude", 0.8, "how far the diurnal rate swings either side of the average, from 0 to 1")
+
+	configPath := fs.String("config", "", "limiter config file, used with -limit")
+	limitName := fs.String("limit", "", "limit to use from the config file")
+	algorithm := fs.String("algorithm", limitconfig.TokenBucket,
+		"algorithm when there is no config file: token_bucket, leaky_bucket, sliding_window, fixed_window or gcra")
+	rate := fs.Int("rate", 10, "rate of the algorithm per interval")
+	interval := fs.Duration("interval", 1*time.Second, "interval of the algorithm")
+	burst := fs.Int("burst", 0, "burst of the algorithm, the rate if 0")
+
+	maxWait := fs.Duration("max-wait", 0, "how long a rejected request waits and retries in total, 0 to not wait")
+	format := fs.String("format", "table", "output format: table or csv")
+	timeline := fs.Bool("timeline", false, "write the accepted and rejected requests per second as CSV instead")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var arrivals []ratesim.Arrival
+	var err error
+	switch {
+	case *trace != "" && *process != "":
+		return errors.New("use either -trace or -process, not both")
+	case *trace != "":
+		arrivals, err = readTrace(*trace)
+	default:
+		p := ratesim.Process{Rate: *ratePerSecond, Duration: *duration, Keys: *keys, Seed: *seed}
+		switch *process {
+		case "poisson", "":
+			arrivals, err = p.Poisson()
+		case "bursty":
+			arrivals, err = p.Bursty(*burstEvery, *burstSize)
+		case "diurnal":
+			arrivals, err = p.Diurnal(*period, *amplitude)
+		default:
+			return fmt.Errorf("unknown process %q, must be poisson, bursty or diurnal", *process)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	cfg, name, err := limitConfig(*configPath, *limitName, limitconfig.LimitConfig{
+		Name:      "simulated",
+		Algorithm: *algorithm,
+		Rate:      *rate,
+		Interval:  limitconfig.Duration(*interval),
+		Burst:     *burst,
+	})
+	if err != nil {
+		return err
+	}
+	newLimiter := func() ratesim.Limiter {
+		// the config is already validated, and limiters are built again so that they start at the simulated time
+		limits, _ := limitconfig.New(cfg)
+		limit, _ := limits.Get(name)
+		return limit
+	}
+
+	report := ratesim.Run(newLimiter, arrivals, *maxWait)
+	switch {
+	case *timeline:
+		return report.WriteTimelineCSV(out)
+	case *format == "csv":
+		return report.WriteCSV(out)
+	case *format == "table":
+		return report.WriteTable(out)
+	default:
+		return fmt.Errorf("unknown format %q, must be table or csv", *format)
+	}
+}
+
+func readTrace(path string) ([]ratesim.Arrival, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson":
+		return ratesim.ReadJSONL(f)
+	default:
+		return ratesim.ReadCSV(f)
+	}
+}
+
+// limitConfig returns the config and name of the limit to simulate, either from the config file or from flags.
+func limitConfig(path string, name string, flags limitconfig.LimitConfig) (limitconfig.Config, string, error) {
+	cfg := limitconfig.Config{Limits: []limitconfig.LimitConfig{flags}}
+	if path == "" {
+		name = flags.Name
+	} else {
+		var err error
+		if cfg, err = limitconfig.Load(path); err != nil {
+			return limitconfig.Config{}, "", err
+		}
+	}
+
+	limits, err := limitconfig.New(cfg)
+	if err != nil {
+		return limitconfig.Config{}, "", err
+	}
+	if _, ok := limits.Get(name); !ok {
+		return limitconfig.Config{}, "", fmt.Errorf("config has no limit called %q that can be simulated", name)
+	}
+	return cfg, name, nil
+}
@@ -1,14 +1,14 @@
 module github.com/edfoh/data-structures
 
-go 1.18
+go 1.23
 
 require (
 	github.com/stretchr/testify v1.7.1
 	golang.org/x/exp v0.0.0-20220722155223-a9213eeb770e
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
 )
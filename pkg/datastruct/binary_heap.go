@@ -3,6 +3,7 @@ package datastruct
 import (
 	"errors"
 	"fmt"
+	"iter"
 	"strings"
 
 	"golang.org/x/exp/constraints"
@@ -108,6 +109,18 @@ func (h *Heap[K, P]) Pop() (K, error) {
 	return val, nil
 }
 
+// All returns an iterator over the items in the order they are stored in, which is not the order they would be
+// popped in. The heap must not be changed while iterating.
+func (h *Heap[K, P]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for _, n := range h.nodes {
+			if !yield(n) {
+				return
+			}
+		}
+	}
+}
+
 func (h *Heap[K, P]) Print() string {
 	var ss []string
 	for _, n := range h.nodes {
@@ -3,6 +3,7 @@ package datastruct
 import (
 	"errors"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -463,3 +464,23 @@ func TestHeapMax_Pop(t *testing.T) {
 		})
 	}
 }
+
+func TestHeap_All(t *testing.T) {
+	h := NewHeap[*testHeapItem[int], int](10, HeapMin)
+	for _, n := range []int{30, 20, 50, 70, 10} {
+		require.NoError(t, h.Insert(&testHeapItem[int]{val: strconv.Itoa(n), priority: n}))
+	}
+
+	var got []string
+	for item := range h.All() {
+		got = append(got, item.String())
+	}
+	assert.Equal(t, strings.Split(h.Print(), ","), got)
+
+	got = nil
+	for item := range h.All() {
+		got = append(got, item.String())
+		break
+	}
+	assert.Equal(t, []string{"10"}, got)
+}
@@ -2,6 +2,7 @@ package datastruct
 
 import (
 	"errors"
+	"iter"
 
 	"golang.org/x/exp/constraints"
 )
@@ -168,3 +169,71 @@ func (tree *BinarySearchTree[K]) inorder(node *TreeNode[K], results *[]K) {
 		tree.inorder(node.right, results)
 	}
 }
+
+// InOrder returns an iterator over the tree in ascending order.
+func (tree *BinarySearchTree[K]) InOrder() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		tree.inorderSeq(tree.root, yield)
+	}
+}
+
+// PreOrder returns an iterator that visits each node before its left and then right subtrees.
+func (tree *BinarySearchTree[K]) PreOrder() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		tree.preorderSeq(tree.root, yield)
+	}
+}
+
+// PostOrder returns an iterator that visits each node after its left and then right subtrees.
+func (tree *BinarySearchTree[K]) PostOrder() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		tree.postorderSeq(tree.root, yield)
+	}
+}
+
+// LevelOrder returns an iterator that visits the tree breadth first, from the root down and left to right within
+// each level.
+func (tree *BinarySearchTree[K]) LevelOrder() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		if tree.root == nil {
+			return
+		}
+		queue := []*TreeNode[K]{tree.root}
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+			if !yield(node.Data()) {
+				return
+			}
+			if node.Left() != nil {
+				queue = append(queue, node.Left())
+			}
+			if node.Right() != nil {
+				queue = append(queue, node.Right())
+			}
+		}
+	}
+}
+
+// inorderSeq, preorderSeq and postorderSeq return false once yield has asked to stop, so that the rest of the
+// tree is not walked.
+func (tree *BinarySearchTree[K]) inorderSeq(node *TreeNode[K], yield func(K) bool) bool {
+	if node == nil {
+		return true
+	}
+	return tree.inorderSeq(node.Left(), yield) && yield(node.Data()) && tree.inorderSeq(node.Right(), yield)
+}
+
+func (tree *BinarySearchTree[K]) preorderSeq(node *TreeNode[K], yield func(K) bool) bool {
+	if node == nil {
+		return true
+	}
+	return yield(node.Data()) && tree.preorderSeq(node.Left(), yield) && tree.preorderSeq(node.Right(), yield)
+}
+
+func (tree *BinarySearchTree[K]) postorderSeq(node *TreeNode[K], yield func(K) bool) bool {
+	if node == nil {
+		return true
+	}
+	return tree.postorderSeq(node.Left(), yield) && tree.postorderSeq(node.Right(), yield) && yield(node.Data())
+}
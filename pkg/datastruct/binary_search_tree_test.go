@@ -2,6 +2,7 @@ package datastruct
 
 import (
 	"errors"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -188,3 +189,77 @@ func TestBinarySearchTree_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestBinarySearchTree_Iterators(t *testing.T) {
+	//        50
+	//      /    \
+	//     30     70
+	//    /  \   /
+	//   20  40 60
+	tree := NewBinarySearchTree[int](10)
+	for _, n := range []int{50, 30, 70, 20, 40, 60} {
+		require.NoError(t, tree.Insert(n))
+	}
+
+	testCases := []struct {
+		desc string
+		seq  func() []int
+		want []int
+	}{
+		{
+			desc: "in order",
+			seq:  func() []int { return slices.Collect(tree.InOrder()) },
+			want: []int{20, 30, 40, 50, 60, 70},
+		},
+		{
+			desc: "pre order",
+			seq:  func() []int { return slices.Collect(tree.PreOrder()) },
+			want: []int{50, 30, 20, 40, 70, 60},
+		},
+		{
+			desc: "post order",
+			seq:  func() []int { return slices.Collect(tree.PostOrder()) },
+			want: []int{20, 40, 30, 60, 70, 50},
+		},
+		{
+			desc: "level order",
+			seq:  func() []int { return slices.Collect(tree.LevelOrder()) },
+			want: []int{50, 30, 70, 20, 40, 60},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.want, tC.seq())
+		})
+	}
+
+	t.Run("in order matches the slice", func(t *testing.T) {
+		assert.Equal(t, tree.InorderResults(), slices.Collect(tree.InOrder()))
+	})
+
+	t.Run("empty tree yields nothing", func(t *testing.T) {
+		empty := NewBinarySearchTree[int](10)
+		assert.Empty(t, slices.Collect(empty.InOrder()))
+		assert.Empty(t, slices.Collect(empty.LevelOrder()))
+	})
+
+	t.Run("stops early", func(t *testing.T) {
+		var got []int
+		for n := range tree.InOrder() {
+			got = append(got, n)
+			if n == 40 {
+				break
+			}
+		}
+		assert.Equal(t, []int{20, 30, 40}, got)
+
+		got = nil
+		for n := range tree.PostOrder() {
+			got = append(got, n)
+			if n == 30 {
+				break
+			}
+		}
+		assert.Equal(t, []int{20, 40, 30}, got)
+	})
+}
@@ -0,0 +1,117 @@
+package datastruct
+
+import "iter"
+
+// CircularList is a doubly linked list whose tail links back to its head, with a cursor on the current node.
+// Moving the cursor round the ring gives round robin over the nodes.
+type CircularList[K comparable, V any] struct {
+	current *doublyNode[K, V]
+	length  int
+}
+
+func NewCircularList[K comparable, V any]() *CircularList[K, V] {
+	return &CircularList[K, V]{current: nil}
+}
+
+// Insert adds the key and item just before the current node, so that it is the last to be reached when moving
+// forward from the current node. The first node inserted becomes the current node.
+func (cl *CircularList[K, V]) Insert(key K, item V) {
+	if cl.current == nil {
+		node := newDoublyNode(key, item, nil, nil)
+		node.Next, node.Previous = node, node
+		cl.current = node
+	} else {
+		cl.current.AddNodeBefore(key, item)
+	}
+	cl.length++
+}
+
+// Current returns the key and item of the current node, or false if the list is empty.
+func (cl *CircularList[K, V]) Current() (K, V, bool) {
+	if cl.current == nil {
+		var key K
+		var item V
+		return key, item, false
+	}
+	return cl.current.Key, cl.current.Item, true
+}
+
+// Next moves the cursor forward one node and returns the new current node.
+func (cl *CircularList[K, V]) Next() (K, V, bool) {
+	return cl.Rotate(1)
+}
+
+// Prev moves the cursor back one node and returns the new current node.
+func (cl *CircularList[K, V]) Prev() (K, V, bool) {
+	return cl.Rotate(-1)
+}
+
+// Rotate moves the cursor n nodes, forward if n is positive and back if negative, and returns the new current
+// node. It goes whichever way round the ring is shorter.
+func (cl *CircularList[K, V]) Rotate(n int) (K, V, bool) {
+	if cl.current == nil {
+		return cl.Current()
+	}
+
+	n %= cl.length
+	if n < 0 {
+		n += cl.length
+	}
+	if n <= cl.length/2 {
+		for ; n > 0; n-- {
+			cl.current = cl.current.Next
+		}
+	} else {
+		for n = cl.length - n; n > 0; n-- {
+			cl.current = cl.current.Previous
+		}
+	}
+	return cl.Current()
+}
+
+// RemoveCurrent removes the current node and returns its key and item, moving the cursor on to the next node.
+func (cl *CircularList[K, V]) RemoveCurrent() (K, V, bool) {
+	key, item, ok := cl.Current()
+	if !ok {
+		return key, item, false
+	}
+
+	node := cl.current
+	if cl.length == 1 {
+		cl.current = nil
+	} else {
+		node.Previous.Next = node.Next
+		node.Next.Previous = node.Previous
+		cl.current = node.Next
+	}
+	node.Next, node.Previous = nil, nil
+	cl.length--
+	return key, item, true
+}
+
+func (cl *CircularList[K, V]) Len() int {
+	return cl.length
+}
+
+// All returns an iterator over the keys and items once round the ring, starting at the current node. The list
+// must not be changed while iterating.
+func (cl *CircularList[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		current := cl.current
+		for i := 0; i < cl.length; i++ {
+			if !yield(current.Key, current.Item) {
+				return
+			}
+			current = current.Next
+		}
+	}
+}
+
+// AllKeys returns the keys once round the ring, starting at the current node.
+func (cl *CircularList[K, V]) AllKeys() []K {
+	var keys []K
+	for key := range cl.All() {
+		keys = append(keys, key)
+	}
+	return keys
+}
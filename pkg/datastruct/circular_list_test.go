@@ -0,0 +1,128 @@
+package datastruct_test
+
+import (
+	"testing"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCircularListOf(keys ...int) *datastruct.CircularList[int, string] {
+	cl := datastruct.NewCircularList[int, string]()
+	for _, key := range keys {
+		cl.Insert(key, "")
+	}
+	return cl
+}
+
+func TestCircularList_Insert(t *testing.T) {
+	cl := datastruct.NewCircularList[int, string]()
+
+	_, _, ok := cl.Current()
+	assert.False(t, ok)
+	assert.Empty(t, cl.AllKeys())
+
+	cl.Insert(1, "1")
+	cl.Insert(2, "2")
+	cl.Insert(3, "3")
+
+	key, item, ok := cl.Current()
+	assert.True(t, ok)
+	assert.Equal(t, 1, key)
+	assert.Equal(t, "1", item)
+	assert.Equal(t, []int{1, 2, 3}, cl.AllKeys())
+	assert.Equal(t, 3, cl.Len())
+}
+
+func TestCircularList_Rotate(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		n       int
+		wantKey int
+	}{
+		{desc: "forward one", n: 1, wantKey: 2},
+		{desc: "forward past the start", n: 6, wantKey: 2},
+		{desc: "forward the shorter way back", n: 4, wantKey: 5},
+		{desc: "back one", n: -1, wantKey: 5},
+		{desc: "back past the start", n: -7, wantKey: 4},
+		{desc: "all the way round", n: 5, wantKey: 1},
+		{desc: "not at all", n: 0, wantKey: 1},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			cl := newCircularListOf(1, 2, 3, 4, 5)
+
+			key, _, ok := cl.Rotate(tC.n)
+
+			assert.True(t, ok)
+			assert.Equal(t, tC.wantKey, key)
+			keys := cl.AllKeys()
+			assert.Equal(t, tC.wantKey, keys[0])
+			assert.Len(t, keys, 5)
+		})
+	}
+
+	t.Run("empty list", func(t *testing.T) {
+		_, _, ok := datastruct.NewCircularList[int, string]().Rotate(3)
+		assert.False(t, ok)
+	})
+}
+
+func TestCircularList_NextPrev(t *testing.T) {
+	cl := newCircularListOf(1, 2, 3)
+
+	var keys []int
+	for i := 0; i < 4; i++ {
+		key, _, _ := cl.Next()
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []int{2, 3, 1, 2}, keys)
+
+	key, _, _ := cl.Prev()
+	assert.Equal(t, 1, key)
+	key, _, _ = cl.Prev()
+	assert.Equal(t, 3, key)
+}
+
+func TestCircularList_RemoveCurrent(t *testing.T) {
+	cl := newCircularListOf(1, 2, 3)
+	cl.Next()
+
+	key, _, ok := cl.RemoveCurrent()
+	assert.True(t, ok)
+	assert.Equal(t, 2, key)
+	assert.Equal(t, []int{3, 1}, cl.AllKeys())
+	assert.Equal(t, 2, cl.Len())
+
+	// inserts still go just before the current node
+	cl.Insert(4, "")
+	assert.Equal(t, []int{3, 1, 4}, cl.AllKeys())
+
+	cl.RemoveCurrent()
+	cl.RemoveCurrent()
+	key, _, ok = cl.RemoveCurrent()
+	assert.True(t, ok)
+	assert.Equal(t, 4, key)
+	assert.Equal(t, 0, cl.Len())
+	assert.Empty(t, cl.AllKeys())
+
+	_, _, ok = cl.RemoveCurrent()
+	assert.False(t, ok)
+
+	cl.Insert(5, "")
+	assert.Equal(t, []int{5}, cl.AllKeys())
+}
+
+func TestCircularList_All(t *testing.T) {
+	cl := newCircularListOf(1, 2, 3)
+	cl.Prev()
+
+	var keys []int
+	for key := range cl.All() {
+		keys = append(keys, key)
+		if key == 1 {
+			break
+		}
+	}
+	assert.Equal(t, []int{3, 1}, keys)
+}
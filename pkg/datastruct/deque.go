@@ -0,0 +1,192 @@
+package datastruct
+
+// Deque is a double-ended queue of values. ArrayDeque and LinkedDeque implement it, and Stack and Queue adapt it.
+type Deque[T any] interface {
+	PushFront(v T)
+	PushBack(v T)
+	// PopFront removes and returns the value at the front, or false if the deque is empty.
+	PopFront() (T, bool)
+	// PopBack removes and returns the value at the back, or false if the deque is empty.
+	PopBack() (T, bool)
+	// Front returns the value at the front without removing it, or false if the deque is empty.
+	Front() (T, bool)
+	// Back returns the value at the back without removing it, or false if the deque is empty.
+	Back() (T, bool)
+	Len() int
+}
+
+// minDequeCapacity is the capacity an ArrayDeque starts with once something is pushed.
+const minDequeCapacity = 8
+
+// ArrayDeque is a Deque on a circular array, which doubles in size when it is full. It only allocates when it
+// grows, and keeps its values next to each other in memory.
+type ArrayDeque[T any] struct {
+	values []T
+	start  int
+	length int
+}
+
+func NewArrayDeque[T any]() *ArrayDeque[T] {
+	return &ArrayDeque[T]{}
+}
+
+func (d *ArrayDeque[T]) PushFront(v T) {
+	d.grow()
+	d.start = d.index(len(d.values) - 1)
+	d.values[d.start] = v
+	d.length++
+}
+
+func (d *ArrayDeque[T]) PushBack(v T) {
+	d.grow()
+	d.values[d.index(d.length)] = v
+	d.length++
+}
+
+func (d *ArrayDeque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.length == 0 {
+		return zero, false
+	}
+	v := d.values[d.start]
+	// clear the slot so that the deque does not keep the value alive
+	d.values[d.start] = zero
+	d.start = d.index(1)
+	d.length--
+	return v, true
+}
+
+func (d *ArrayDeque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.length == 0 {
+		return zero, false
+	}
+	i := d.index(d.length - 1)
+	v := d.values[i]
+	d.values[i] = zero
+	d.length--
+	return v, true
+}
+
+func (d *ArrayDeque[T]) Front() (T, bool) {
+	if d.length == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.values[d.start], true
+}
+
+func (d *ArrayDeque[T]) Back() (T, bool) {
+	if d.length == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.values[d.index(d.length-1)], true
+}
+
+func (d *ArrayDeque[T]) Len() int {
+	return d.length
+}
+
+// grow doubles the array if it is full, copying the values over in order from the start of the new array.
+func (d *ArrayDeque[T]) grow() {
+	if d.length < len(d.values) {
+		return
+	}
+	values := make([]T, max(minDequeCapacity, len(d.values)*2))
+	n := copy(values, d.values[d.start:])
+	copy(values[n:], d.values[:d.start])
+	d.values = values
+	d.start = 0
+}
+
+// index returns the position in the array of the i-th value from the front.
+func (d *ArrayDeque[T]) index(i int) int {
+	return (d.start + i) % len(d.values)
+}
+
+// LinkedDeque is a Deque on doubly linked nodes. It allocates a node for every value pushed, but never has to
+// copy values to grow.
+type LinkedDeque[T any] struct {
+	head   *doublyNode[struct{}, T]
+	tail   *doublyNode[struct{}, T]
+	length int
+}
+
+func NewLinkedDeque[T any]() *LinkedDeque[T] {
+	return &LinkedDeque[T]{head: nil, tail: nil}
+}
+
+func (d *LinkedDeque[T]) PushFront(v T) {
+	if d.head == nil {
+		d.head = newDoublyNode(struct{}{}, v, nil, nil)
+		d.tail = d.head
+	} else {
+		d.head = d.head.AddNodeBefore(struct{}{}, v)
+	}
+	d.length++
+}
+
+func (d *LinkedDeque[T]) PushBack(v T) {
+	if d.tail == nil {
+		d.tail = newDoublyNode(struct{}{}, v, nil, nil)
+		d.head = d.tail
+	} else {
+		d.tail = d.tail.AddNodeAfter(struct{}{}, v)
+	}
+	d.length++
+}
+
+func (d *LinkedDeque[T]) PopFront() (T, bool) {
+	if d.head == nil {
+		var zero T
+		return zero, false
+	}
+	node := d.head
+	d.head = node.Next
+	if d.head == nil {
+		d.tail = nil
+	} else {
+		d.head.Previous = nil
+	}
+	node.Next = nil
+	d.length--
+	return node.Item, true
+}
+
+func (d *LinkedDeque[T]) PopBack() (T, bool) {
+	if d.tail == nil {
+		var zero T
+		return zero, false
+	}
+	node := d.tail
+	d.tail = node.Previous
+	if d.tail == nil {
+		d.head = nil
+	} else {
+		d.tail.Next = nil
+	}
+	node.Previous = nil
+	d.length--
+	return node.Item, true
+}
+
+func (d *LinkedDeque[T]) Front() (T, bool) {
+	if d.head == nil {
+		var zero T
+		return zero, false
+	}
+	return d.head.Item, true
+}
+
+func (d *LinkedDeque[T]) Back() (T, bool) {
+	if d.tail == nil {
+		var zero T
+		return zero, false
+	}
+	return d.tail.Item, true
+}
+
+func (d *LinkedDeque[T]) Len() int {
+	return d.length
+}
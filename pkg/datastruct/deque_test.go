@@ -0,0 +1,254 @@
+package datastruct_test
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var deques = []struct {
+	name     string
+	newDeque func() datastruct.Deque[int]
+}{
+	{name: "array", newDeque: func() datastruct.Deque[int] { return datastruct.NewArrayDeque[int]() }},
+	{name: "linked", newDeque: func() datastruct.Deque[int] { return datastruct.NewLinkedDeque[int]() }},
+}
+
+func TestDeque(t *testing.T) {
+	for _, d := range deques {
+		t.Run(d.name, func(t *testing.T) {
+			t.Run("empty deque", func(t *testing.T) {
+				dq := d.newDeque()
+
+				for _, op := range []func() (int, bool){dq.PopFront, dq.PopBack, dq.Front, dq.Back} {
+					_, ok := op()
+					assert.False(t, ok)
+				}
+				assert.Equal(t, 0, dq.Len())
+			})
+
+			t.Run("push and pop at both ends", func(t *testing.T) {
+				dq := d.newDeque()
+				dq.PushBack(2)
+				dq.PushFront(1)
+				dq.PushBack(3)
+
+				front, _ := dq.Front()
+				back, _ := dq.Back()
+				assert.Equal(t, 1, front)
+				assert.Equal(t, 3, back)
+				assert.Equal(t, 3, dq.Len())
+
+				v, ok := dq.PopFront()
+				assert.True(t, ok)
+				assert.Equal(t, 1, v)
+				v, ok = dq.PopBack()
+				assert.True(t, ok)
+				assert.Equal(t, 3, v)
+				v, ok = dq.PopBack()
+				assert.True(t, ok)
+				assert.Equal(t, 2, v)
+
+				_, ok = dq.PopFront()
+				assert.False(t, ok)
+				assert.Equal(t, 0, dq.Len())
+
+				dq.PushFront(4)
+				front, _ = dq.Front()
+				back, _ = dq.Back()
+				assert.Equal(t, 4, front)
+				assert.Equal(t, 4, back)
+			})
+
+			t.Run("matches a slice under random operations", func(t *testing.T) {
+				r := rand.New(rand.NewSource(1))
+				dq := d.newDeque()
+				var want []int
+
+				// enough operations to grow the array deque several times and wrap round its end
+				for i := 0; i < 2000; i++ {
+					switch op := r.Intn(5); {
+					case op == 0:
+						dq.PushFront(i)
+						want = append([]int{i}, want...)
+					case op <= 2:
+						dq.PushBack(i)
+						want = append(want, i)
+					case op == 3:
+						v, ok := dq.PopFront()
+						require.Equal(t, len(want) > 0, ok)
+						if ok {
+							require.Equal(t, want[0], v)
+							want = want[1:]
+						}
+					default:
+						v, ok := dq.PopBack()
+						require.Equal(t, len(want) > 0, ok)
+						if ok {
+							require.Equal(t, want[len(want)-1], v)
+							want = want[:len(want)-1]
+						}
+					}
+					require.Equal(t, len(want), dq.Len())
+				}
+			})
+		})
+	}
+}
+
+func TestStack(t *testing.T) {
+	for _, d := range deques {
+		t.Run(d.name, func(t *testing.T) {
+			s := datastruct.NewStack(d.newDeque())
+			s.Push(1)
+			s.Push(2)
+			s.Push(3)
+
+			top, ok := s.Peek()
+			assert.True(t, ok)
+			assert.Equal(t, 3, top)
+			assert.Equal(t, 3, s.Len())
+
+			var popped []int
+			for {
+				v, ok := s.Pop()
+				if !ok {
+					break
+				}
+				popped = append(popped, v)
+			}
+			assert.Equal(t, []int{3, 2, 1}, popped)
+			_, ok = s.Peek()
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestQueue(t *testing.T) {
+	for _, d := range deques {
+		t.Run(d.name, func(t *testing.T) {
+			q := datastruct.NewQueue(d.newDeque())
+			q.Enqueue(1)
+			q.Enqueue(2)
+			q.Enqueue(3)
+
+			first, ok := q.Peek()
+			assert.True(t, ok)
+			assert.Equal(t, 1, first)
+			assert.Equal(t, 3, q.Len())
+
+			var dequeued []int
+			for {
+				v, ok := q.Dequeue()
+				if !ok {
+					break
+				}
+				dequeued = append(dequeued, v)
+			}
+			assert.Equal(t, []int{1, 2, 3}, dequeued)
+			_, ok = q.Peek()
+			assert.False(t, ok)
+		})
+	}
+}
+
+// benchSizes are how many values are held at once, from what fits in cache to what does not.
+var benchSizes = []int{16, 1024, 65536}
+
+// BenchmarkQueue fills a queue then dequeues it, comparing the deques with the lists used as a queue. The
+// lists need a key for every value and allocate a node for every insert.
+func BenchmarkQueue(b *testing.B) {
+	for _, size := range benchSizes {
+		for _, d := range deques {
+			b.Run(d.name+"/"+strconv.Itoa(size), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					q := datastruct.NewQueue(d.newDeque())
+					for v := 0; v < size; v++ {
+						q.Enqueue(v)
+					}
+					for q.Len() > 0 {
+						q.Dequeue()
+					}
+				}
+			})
+		}
+		b.Run("double_linked_list/"+strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				dll := datastruct.NewDoubleLinkedList[int, int]()
+				for v := 0; v < size; v++ {
+					dll.InsertTail(v, v)
+				}
+				for dll.DeleteHead() {
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkStack fills a stack then pops it, comparing the deques with the lists used as a stack.
+func BenchmarkStack(b *testing.B) {
+	for _, size := range benchSizes {
+		for _, d := range deques {
+			b.Run(d.name+"/"+strconv.Itoa(size), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					s := datastruct.NewStack(d.newDeque())
+					for v := 0; v < size; v++ {
+						s.Push(v)
+					}
+					for s.Len() > 0 {
+						s.Pop()
+					}
+				}
+			})
+		}
+		b.Run("double_linked_list/"+strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				dll := datastruct.NewDoubleLinkedList[int, int]()
+				for v := 0; v < size; v++ {
+					dll.InsertTail(v, v)
+				}
+				for dll.DeleteTail() {
+				}
+			}
+		})
+		b.Run("single_linked_list/"+strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sll := datastruct.NewSingleLinkedList[int, int]()
+				for v := 0; v < size; v++ {
+					sll.InsertHead(v, v)
+				}
+				for sll.Len() > 0 {
+					sll.RemoveAt(0)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkDeque_Steady keeps a deque at a steady size, pushing at the back and popping from the front, which is
+// where the array deque stops allocating once it has grown.
+func BenchmarkDeque_Steady(b *testing.B) {
+	for _, d := range deques {
+		b.Run(d.name, func(b *testing.B) {
+			b.ReportAllocs()
+			dq := d.newDeque()
+			for v := 0; v < 1024; v++ {
+				dq.PushBack(v)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				dq.PushBack(i)
+				dq.PopFront()
+			}
+		})
+	}
+}
@@ -1,100 +1,247 @@
 package datastruct
 
+import (
+	"errors"
+	"iter"
+)
+
 type DoubleLinkedList[K comparable, V any] struct {
-	head *doublyNode[K, V]
-	tail *doublyNode[K, V]
+	head   *doublyNode[K, V]
+	tail   *doublyNode[K, V]
+	length int
+	// handles is how many nodes in the list have an element handed out
+	handles int
+	policy  DuplicatePolicy
+	// index is only set once WithIndex is called
+	index map[K]*doublyNode[K, V]
 }
 
 func NewDoubleLinkedList[K comparable, V any]() *DoubleLinkedList[K, V] {
 	return &DoubleLinkedList[K, V]{head: nil, tail: nil}
 }
 
-func (dll *DoubleLinkedList[K, V]) InsertHead(key K, item V) {
-	if dll.isEmpty() {
-		dll.insertFirstItem(key, item)
-	} else {
-		dll.head = dll.head.AddNodeBefore(key, item)
+// WithDuplicatePolicy sets what inserting a key that is already in the list does, which is AllowDuplicates by
+// default. It should be set before anything is inserted. An indexed list cannot hold duplicates, so it treats
+// AllowDuplicates as UpsertDuplicates.
+func (dll *DoubleLinkedList[K, V]) WithDuplicatePolicy(policy DuplicatePolicy) *DoubleLinkedList[K, V] {
+	if dll.index != nil && policy == AllowDuplicates {
+		policy = UpsertDuplicates
 	}
+	dll.policy = policy
+	return dll
 }
 
-func (dll *DoubleLinkedList[K, V]) InsertTail(key K, item V) {
-	if dll.isEmpty() {
-		dll.insertFirstItem(key, item)
-	} else {
-		dll.tail = dll.tail.AddNodeAfter(key, item)
+// WithIndex keeps a map from each key to its node, so that Search, Delete, InsertAfter and the duplicate checks
+// are O(1) instead of walking the list, at the cost of the memory for the map. Keys have to be unique, so a list
+// that allows duplicates is switched to UpsertDuplicates. It should be set before anything is inserted, otherwise
+// the duplicate policy is applied to the keys already in the list and only the first node of each key is kept.
+func (dll *DoubleLinkedList[K, V]) WithIndex() *DoubleLinkedList[K, V] {
+	if dll.index != nil {
+		return dll
 	}
+	dll.index = make(map[K]*doublyNode[K, V])
+	dll.WithDuplicatePolicy(dll.policy)
+	current := dll.head
+	for current != nil {
+		node := current
+		current = current.Next
+		if existing := dll.index[node.Key]; existing != nil {
+			if dll.policy == UpsertDuplicates {
+				existing.Item = node.Item
+			}
+			dll.remove(node)
+			continue
+		}
+		dll.index[node.Key] = node
+	}
+	return dll
+}
+
+// InsertHead adds the key and item at the head. A key the list rejects is not inserted, TryInsertHead reports it.
+func (dll *DoubleLinkedList[K, V]) InsertHead(key K, item V) {
+	dll.addHead(key, item)
 }
 
+// TryInsertHead adds the key and item at the head, returning the element that holds them, or ErrDuplicateKey if
+// the list rejects duplicates and already has the key.
+func (dll *DoubleLinkedList[K, V]) TryInsertHead(key K, item V) (*Element[K, V], error) {
+	return dll.elementOf(dll.addHead(key, item))
+}
+
+// InsertTail adds the key and item at the tail. A key the list rejects is not inserted, TryInsertTail reports it.
+func (dll *DoubleLinkedList[K, V]) InsertTail(key K, item V) {
+	dll.addTail(key, item)
+}
+
+// TryInsertTail adds the key and item at the tail, returning the element that holds them, or ErrDuplicateKey if
+// the list rejects duplicates and already has the key.
+func (dll *DoubleLinkedList[K, V]) TryInsertTail(key K, item V) (*Element[K, V], error) {
+	return dll.elementOf(dll.addTail(key, item))
+}
+
+// InsertAfter adds the key and item after keyAfter, returning false if keyAfter is not in the list or the list
+// rejects keyInsert.
 func (dll *DoubleLinkedList[K, V]) InsertAfter(keyAfter K, keyInsert K, itemInsert V) bool {
-	node := dll.findNode(keyAfter)
-	if node != nil {
-		newNode := node.AddNodeAfter(keyInsert, itemInsert)
-		if newNode.IsTail() {
-			dll.tail = newNode
+	_, err := dll.addAfter(keyAfter, keyInsert, itemInsert)
+	return err == nil
+}
+
+// TryInsertAfter adds the key and item after keyAfter, returning the element that holds them, or an error if
+// keyAfter is not in the list or the list rejects keyInsert.
+func (dll *DoubleLinkedList[K, V]) TryInsertAfter(keyAfter K, keyInsert K, itemInsert V) (*Element[K, V], error) {
+	return dll.elementOf(dll.addAfter(keyAfter, keyInsert, itemInsert))
+}
+
+// Search returns the item of the node with key nearest the head.
+func (dll *DoubleLinkedList[K, V]) Search(key K) (bool, V) {
+	var val V
+	node := dll.findNode(key)
+	if node == nil {
+		return false, val
+	}
+	return true, node.Item
+}
+
+// SearchAll returns the items of every node with key, from head to tail.
+func (dll *DoubleLinkedList[K, V]) SearchAll(key K) []V {
+	if dll.index != nil {
+		if node := dll.index[key]; node != nil {
+			return []V{node.Item}
 		}
-		return true
+		return nil
 	}
-	return false
+
+	var vals []V
+	for current := dll.head; current != nil; current = current.Next {
+		if current.Key == key {
+			vals = append(vals, current.Item)
+		}
+	}
+	return vals
 }
 
 func (dll *DoubleLinkedList[K, V]) Delete(key K) bool {
 	node := dll.findNode(key)
 	if node != nil {
-		if node.Previous != nil {
-			node.Previous.Next = node.Next
-		} else { // node is head
-			dll.head = node.Next
-		}
-		if node.Next != nil {
-			node.Next.Previous = node.Previous
-		} else { // node is tail
-			dll.tail = node.Previous
-		}
-		node.Previous = nil
-		node.Next = nil
+		dll.remove(node)
 		return true
 	}
 	return false
 }
 
+// DeleteAll deletes every node with key, returning how many were deleted.
+func (dll *DoubleLinkedList[K, V]) DeleteAll(key K) int {
+	if dll.index != nil {
+		if dll.Delete(key) {
+			return 1
+		}
+		return 0
+	}
+
+	deleted := 0
+	current := dll.head
+	for current != nil {
+		next := current.Next
+		if current.Key == key {
+			dll.remove(current)
+			deleted++
+		}
+		current = next
+	}
+	return deleted
+}
+
 func (dll *DoubleLinkedList[K, V]) DeleteHead() bool {
 	if dll.isEmpty() {
 		return false
 	}
-	if dll.head == dll.tail {
-		dll.head = nil
-		dll.tail = nil
-		return true
-	} else if dll.head.Next == dll.tail {
-		dll.head = dll.tail
-		dll.head.Next = nil
-		dll.tail.Previous = nil
-		return true
-	} else {
-		dll.head = dll.head.Next
-		dll.head.Previous = nil
-		return true
-	}
+	dll.remove(dll.head)
+	return true
 }
 
 func (dll *DoubleLinkedList[K, V]) DeleteTail() bool {
 	if dll.isEmpty() {
 		return false
 	}
-	if dll.head == dll.tail {
-		dll.head = nil
-		dll.tail = nil
-		return true
-	} else if dll.tail.Previous == dll.head {
-		dll.tail = dll.head
-		dll.head.Next = nil
-		dll.tail.Previous = nil
-		return true
-	} else {
-		dll.tail = dll.tail.Previous
-		dll.tail.Next = nil
-		return true
+	dll.remove(dll.tail)
+	return true
+}
+
+// FrontElement returns the element at the head, or nil if the list is empty.
+func (dll *DoubleLinkedList[K, V]) FrontElement() *Element[K, V] {
+	if dll.isEmpty() {
+		return nil
+	}
+	return dll.element(dll.head)
+}
+
+// BackElement returns the element at the tail, or nil if the list is empty.
+func (dll *DoubleLinkedList[K, V]) BackElement() *Element[K, V] {
+	if dll.isEmpty() {
+		return nil
 	}
+	return dll.element(dll.tail)
+}
+
+// Front returns the key and item at the head without removing them, or false if the list is empty.
+func (dll *DoubleLinkedList[K, V]) Front() (K, V, bool) {
+	if dll.isEmpty() {
+		var key K
+		var item V
+		return key, item, false
+	}
+	return dll.head.Key, dll.head.Item, true
+}
+
+// Back returns the key and item at the tail without removing them, or false if the list is empty.
+func (dll *DoubleLinkedList[K, V]) Back() (K, V, bool) {
+	if dll.isEmpty() {
+		var key K
+		var item V
+		return key, item, false
+	}
+	return dll.tail.Key, dll.tail.Item, true
+}
+
+// Len returns the number of nodes in the list.
+func (dll *DoubleLinkedList[K, V]) Len() int {
+	return dll.length
+}
+
+// At returns the key and item at index i counting from 0 at the head, or false if i is out of range.
+func (dll *DoubleLinkedList[K, V]) At(i int) (K, V, bool) {
+	node := dll.nodeAt(i)
+	if node == nil {
+		var key K
+		var item V
+		return key, item, false
+	}
+	return node.Key, node.Item, true
+}
+
+// InsertAt adds the key and item so that they end up at index i, which can be from 0 up to Len, returning false if
+// i is out of range or the list rejects the key.
+func (dll *DoubleLinkedList[K, V]) InsertAt(i int, key K, item V) bool {
+	_, err := dll.addAt(i, key, item)
+	return err == nil
+}
+
+// TryInsertAt adds the key and item so that they end up at index i, which can be from 0 up to Len, returning the
+// element that holds them, or an error if i is out of range or the list rejects the key.
+func (dll *DoubleLinkedList[K, V]) TryInsertAt(i int, key K, item V) (*Element[K, V], error) {
+	return dll.elementOf(dll.addAt(i, key, item))
+}
+
+// RemoveAt removes the node at index i and returns its key and item, or false if i is out of range.
+func (dll *DoubleLinkedList[K, V]) RemoveAt(i int) (K, V, bool) {
+	node := dll.nodeAt(i)
+	if node == nil {
+		var key K
+		var item V
+		return key, item, false
+	}
+	dll.remove(node)
+	return node.Key, node.Item, true
 }
 
 func (dll *DoubleLinkedList[K, V]) AllKeys() []K {
@@ -137,20 +284,462 @@ func (dll *DoubleLinkedList[K, V]) AllItemsReverse() []V {
 	return vals
 }
 
+// PushBackList moves the nodes of other to the tail of the list, leaving other empty. It is O(1) unless elements
+// of other have been handed out or either list is indexed, in which case they are moved over to the list in O(n).
+// An indexed list applies its duplicate policy to the keys of other, so rejected nodes are left in other.
+func (dll *DoubleLinkedList[K, V]) PushBackList(other *DoubleLinkedList[K, V]) {
+	if other == dll || other.isEmpty() {
+		return
+	}
+	taken := dll.take(other)
+	if taken.isEmpty() {
+		return
+	}
+	if dll.isEmpty() {
+		dll.head = taken.head
+	} else {
+		dll.tail.Next = taken.head
+		taken.head.Previous = dll.tail
+	}
+	dll.tail = taken.tail
+	dll.length += taken.length
+	taken.head, taken.tail, taken.length = nil, nil, 0
+}
+
+// PushFrontList moves the nodes of other to the head of the list, leaving other empty. It is O(1) unless elements
+// of other have been handed out or either list is indexed, in which case they are moved over to the list in O(n).
+// An indexed list applies its duplicate policy to the keys of other, so rejected nodes are left in other.
+func (dll *DoubleLinkedList[K, V]) PushFrontList(other *DoubleLinkedList[K, V]) {
+	if other == dll || other.isEmpty() {
+		return
+	}
+	taken := dll.take(other)
+	if taken.isEmpty() {
+		return
+	}
+	if dll.isEmpty() {
+		dll.tail = taken.tail
+	} else {
+		taken.tail.Next = dll.head
+		dll.head.Previous = taken.tail
+	}
+	dll.head = taken.head
+	dll.length += taken.length
+	taken.head, taken.tail, taken.length = nil, nil, 0
+}
+
+// Reverse reverses the order of the list in place.
+func (dll *DoubleLinkedList[K, V]) Reverse() {
+	for current := dll.head; current != nil; current = current.Previous {
+		current.Next, current.Previous = current.Previous, current.Next
+	}
+	dll.head, dll.tail = dll.tail, dll.head
+}
+
+// SplitAt keeps the first i nodes in the list and moves the rest into a new list, which is returned. The new list
+// has the same duplicate policy and is indexed if the list is.
+func (dll *DoubleLinkedList[K, V]) SplitAt(i int) *DoubleLinkedList[K, V] {
+	rest := NewDoubleLinkedList[K, V]().WithDuplicatePolicy(dll.policy)
+	if dll.index != nil {
+		rest.WithIndex()
+	}
+	if i <= 0 {
+		rest.PushBackList(dll)
+		return rest
+	}
+
+	if i >= dll.length {
+		return rest
+	}
+
+	last := dll.nodeAt(i - 1)
+	rest.head, rest.tail, rest.length = last.Next, dll.tail, dll.length-i
+	rest.head.Previous = nil
+	last.Next = nil
+	dll.tail, dll.length = last, i
+	rest.takeHandles(dll, rest.head)
+	rest.takeIndex(dll, rest.head)
+	return rest
+}
+
+// MergeSorted merges the nodes of other into the list, leaving other empty. Both lists must already be sorted
+// by less on their items, and equal items from the list come before those from other. An indexed list applies its
+// duplicate policy to the keys of other, the same as PushBackList.
+func (dll *DoubleLinkedList[K, V]) MergeSorted(other *DoubleLinkedList[K, V], less func(a, b V) bool) {
+	if other == dll {
+		return
+	}
+	taken := dll.take(other)
+	dll.setNodes(mergeDoubly(dll.head, taken.head, less))
+	dll.length += taken.length
+	taken.head, taken.tail, taken.length = nil, nil, 0
+}
+
+// Sort sorts the list by less on the items with a merge sort on the nodes. It is stable, so equal items keep
+// their order.
+func (dll *DoubleLinkedList[K, V]) Sort(less func(a, b V) bool) {
+	dll.setNodes(sortDoubly(dll.head, less))
+}
+
+// setNodes makes head the head of the list, fixing the links back to the previous nodes that the sort and merge
+// do not keep, and walking to its tail.
+func (dll *DoubleLinkedList[K, V]) setNodes(head *doublyNode[K, V]) {
+	dll.head = head
+	var previous *doublyNode[K, V]
+	for current := head; current != nil; current = current.Next {
+		current.Previous = previous
+		previous = current
+	}
+	dll.tail = previous
+}
+
+// take returns the nodes of other that are moving over to the list, ready to be linked in. Without an index that
+// is all of other. An indexed list applies its duplicate policy to each key: an upserted node replaces the item of
+// the node already in the list and is dropped, a rejected node is left in other, and the rest are moved out of
+// other into a new list.
+func (dll *DoubleLinkedList[K, V]) take(other *DoubleLinkedList[K, V]) *DoubleLinkedList[K, V] {
+	if dll.index == nil {
+		dll.takeHandles(other, other.head)
+		dll.takeIndex(other, other.head)
+		return other
+	}
+
+	taken := NewDoubleLinkedList[K, V]()
+	current := other.head
+	for current != nil {
+		node := current
+		current = current.Next
+		if existing := dll.index[node.Key]; existing != nil {
+			if dll.policy == UpsertDuplicates {
+				existing.Item = node.Item
+				other.remove(node)
+			}
+			continue
+		}
+		other.detach(node)
+		if node.element != nil {
+			node.element.list = dll
+			other.handles--
+			dll.handles++
+		}
+		taken.pushBack(node)
+		taken.length++
+		dll.index[node.Key] = node
+	}
+	return taken
+}
+
+// takeHandles moves the elements of the nodes from first onwards, which are being moved over from the other
+// list, to this list.
+func (dll *DoubleLinkedList[K, V]) takeHandles(other *DoubleLinkedList[K, V], first *doublyNode[K, V]) {
+	for current := first; current != nil && other.handles > 0; current = current.Next {
+		if current.element != nil {
+			current.element.list = dll
+			other.handles--
+			dll.handles++
+		}
+	}
+}
+
+// takeIndex moves the keys of the nodes from first onwards, which are being moved over from the other list, from
+// the other list's index to this list's.
+func (dll *DoubleLinkedList[K, V]) takeIndex(other *DoubleLinkedList[K, V], first *doublyNode[K, V]) {
+	if other.index != nil {
+		for current := first; current != nil; current = current.Next {
+			if other.index[current.Key] == current {
+				delete(other.index, current.Key)
+			}
+		}
+	}
+	dll.addToIndex(first)
+}
+
+// addToIndex adds the nodes from first onwards to the index, if the list has one.
+func (dll *DoubleLinkedList[K, V]) addToIndex(first *doublyNode[K, V]) {
+	if dll.index == nil {
+		return
+	}
+	for current := first; current != nil; current = current.Next {
+		dll.index[current.Key] = current
+	}
+}
+
+// sortDoubly splits the nodes from head in half, sorts each half and merges them, only following the links to the
+// next nodes.
+func sortDoubly[K comparable, V any](head *doublyNode[K, V], less func(a, b V) bool) *doublyNode[K, V] {
+	if head == nil || head.IsTail() {
+		return head
+	}
+
+	slow, fast := head, head.Next
+	for fast != nil && fast.Next != nil {
+		slow = slow.Next
+		fast = fast.Next.Next
+	}
+	mid := slow.Next
+	slow.Next = nil
+
+	return mergeDoubly(sortDoubly(head, less), sortDoubly(mid, less), less)
+}
+
+// mergeDoubly merges the sorted nodes from a and b, taking from a when items are equal so that the merge is
+// stable. Only the links to the next nodes are set.
+func mergeDoubly[K comparable, V any](a *doublyNode[K, V], b *doublyNode[K, V], less func(a, b V) bool) *doublyNode[K, V] {
+	var start doublyNode[K, V]
+	last := &start
+	for a != nil && b != nil {
+		if less(b.Item, a.Item) {
+			last.Next, b = b, b.Next
+		} else {
+			last.Next, a = a, a.Next
+		}
+		last = last.Next
+	}
+	if a != nil {
+		last.Next = a
+	} else {
+		last.Next = b
+	}
+	return start.Next
+}
+
+// All returns an iterator over the keys and items from head to tail.
+func (dll *DoubleLinkedList[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for current := dll.head; current != nil; current = current.Next {
+			if !yield(current.Key, current.Item) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the keys and items from tail to head.
+func (dll *DoubleLinkedList[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for current := dll.tail; current != nil; current = current.Previous {
+			if !yield(current.Key, current.Item) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over the keys from head to tail.
+func (dll *DoubleLinkedList[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range dll.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the items from head to tail.
+func (dll *DoubleLinkedList[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, item := range dll.All() {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// findNode returns the node with key nearest the head, the same as SingleLinkedList.
 func (dll *DoubleLinkedList[K, V]) findNode(key K) *doublyNode[K, V] {
-	current := dll.tail
+	if dll.index != nil {
+		return dll.index[key]
+	}
+	current := dll.head
 	for current != nil {
 		if current.Key == key {
 			return current
 		}
-		current = current.Previous
+		current = current.Next
 	}
 	return nil
 }
 
+func (dll *DoubleLinkedList[K, V]) addHead(key K, item V) (*doublyNode[K, V], error) {
+	return dll.insert(key, item, func() *doublyNode[K, V] {
+		if dll.isEmpty() {
+			dll.insertFirstItem(key, item)
+		} else {
+			dll.head = dll.head.AddNodeBefore(key, item)
+			dll.length++
+		}
+		return dll.head
+	})
+}
+
+func (dll *DoubleLinkedList[K, V]) addTail(key K, item V) (*doublyNode[K, V], error) {
+	return dll.insert(key, item, func() *doublyNode[K, V] {
+		if dll.isEmpty() {
+			dll.insertFirstItem(key, item)
+		} else {
+			dll.tail = dll.tail.AddNodeAfter(key, item)
+			dll.length++
+		}
+		return dll.tail
+	})
+}
+
+func (dll *DoubleLinkedList[K, V]) addAfter(keyAfter K, keyInsert K, itemInsert V) (*doublyNode[K, V], error) {
+	node := dll.findNode(keyAfter)
+	if node == nil {
+		return nil, errors.New("key not found")
+	}
+	return dll.insert(keyInsert, itemInsert, func() *doublyNode[K, V] {
+		return dll.insertAfter(node, keyInsert, itemInsert)
+	})
+}
+
+func (dll *DoubleLinkedList[K, V]) addAt(i int, key K, item V) (*doublyNode[K, V], error) {
+	if i < 0 || i > dll.length {
+		return nil, errors.New("index out of range")
+	}
+	if i == dll.length {
+		return dll.addTail(key, item)
+	}
+	return dll.insert(key, item, func() *doublyNode[K, V] {
+		return dll.insertBefore(dll.nodeAt(i), key, item)
+	})
+}
+
+// insert applies the duplicate policy to key, only calling insert to add a new node if the key is not rejected or
+// upserted, and returns the node that now holds the item.
+func (dll *DoubleLinkedList[K, V]) insert(key K, item V, insert func() *doublyNode[K, V]) (*doublyNode[K, V], error) {
+	if dll.policy != AllowDuplicates {
+		if node := dll.findNode(key); node != nil {
+			if dll.policy == RejectDuplicates {
+				return nil, ErrDuplicateKey
+			}
+			node.Item = item
+			return node, nil
+		}
+	}
+	node := insert()
+	if dll.index != nil {
+		dll.index[key] = node
+	}
+	return node, nil
+}
+
+func (dll *DoubleLinkedList[K, V]) insertBefore(node *doublyNode[K, V], key K, item V) *doublyNode[K, V] {
+	newNode := node.AddNodeBefore(key, item)
+	if newNode.IsHead() {
+		dll.head = newNode
+	}
+	dll.length++
+	return newNode
+}
+
+func (dll *DoubleLinkedList[K, V]) insertAfter(node *doublyNode[K, V], key K, item V) *doublyNode[K, V] {
+	newNode := node.AddNodeAfter(key, item)
+	if newNode.IsTail() {
+		dll.tail = newNode
+	}
+	dll.length++
+	return newNode
+}
+
+// unlink takes node out of the list, leaving it detached so that it can be linked back in elsewhere.
+func (dll *DoubleLinkedList[K, V]) unlink(node *doublyNode[K, V]) {
+	if node.Previous != nil {
+		node.Previous.Next = node.Next
+	} else { // node is head
+		dll.head = node.Next
+	}
+	if node.Next != nil {
+		node.Next.Previous = node.Previous
+	} else { // node is tail
+		dll.tail = node.Previous
+	}
+	node.Previous = nil
+	node.Next = nil
+}
+
+// detach unlinks node and takes it out of the count and the index, leaving its element to whoever takes the node.
+func (dll *DoubleLinkedList[K, V]) detach(node *doublyNode[K, V]) {
+	dll.unlink(node)
+	dll.length--
+	if dll.index != nil && dll.index[node.Key] == node {
+		delete(dll.index, node.Key)
+	}
+}
+
+// remove unlinks node for good, so that its element no longer belongs to the list.
+func (dll *DoubleLinkedList[K, V]) remove(node *doublyNode[K, V]) {
+	dll.detach(node)
+	if node.element != nil {
+		node.element.list = nil
+		dll.handles--
+	}
+}
+
+func (dll *DoubleLinkedList[K, V]) pushFront(node *doublyNode[K, V]) {
+	if dll.isEmpty() {
+		dll.head, dll.tail = node, node
+		return
+	}
+	node.Next = dll.head
+	dll.head.Previous = node
+	dll.head = node
+}
+
+func (dll *DoubleLinkedList[K, V]) pushBack(node *doublyNode[K, V]) {
+	if dll.isEmpty() {
+		dll.head, dll.tail = node, node
+		return
+	}
+	node.Previous = dll.tail
+	dll.tail.Next = node
+	dll.tail = node
+}
+
+// elementOf returns the element of the node an insert returned, so that handles are only created for the inserts
+// that hand them out.
+func (dll *DoubleLinkedList[K, V]) elementOf(node *doublyNode[K, V], err error) (*Element[K, V], error) {
+	if err != nil {
+		return nil, err
+	}
+	return dll.element(node), nil
+}
+
+// element returns the handle for node, creating it the first time it is asked for.
+func (dll *DoubleLinkedList[K, V]) element(node *doublyNode[K, V]) *Element[K, V] {
+	if node.element == nil {
+		node.element = &Element[K, V]{list: dll, node: node}
+		dll.handles++
+	}
+	return node.element
+}
+
+// nodeAt returns the node at index i, walking from whichever end is closer, or nil if i is out of range.
+func (dll *DoubleLinkedList[K, V]) nodeAt(i int) *doublyNode[K, V] {
+	if i < 0 || i >= dll.length {
+		return nil
+	}
+	if i < dll.length/2 {
+		current := dll.head
+		for ; i > 0; i-- {
+			current = current.Next
+		}
+		return current
+	}
+	current := dll.tail
+	for j := dll.length - 1; j > i; j-- {
+		current = current.Previous
+	}
+	return current
+}
+
 func (dll *DoubleLinkedList[K, V]) insertFirstItem(key K, item V) {
 	dll.tail = newDoublyNode(key, item, nil, nil)
 	dll.head = dll.tail
+	dll.length = 1
 }
 
 func (dll *DoubleLinkedList[K, V]) isEmpty() bool {
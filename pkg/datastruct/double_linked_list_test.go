@@ -1,6 +1,9 @@
 package datastruct_test
 
 import (
+	"errors"
+	"slices"
+	"strconv"
 	"testing"
 
 	"github.com/edfoh/data-structures/pkg/datastruct"
@@ -262,3 +265,505 @@ func TestDoubleLinkedList_DeleteTail(t *testing.T) {
 		assert.Empty(t, dll.AllItemsReverse())
 	})
 }
+
+func TestDoubleLinkedList_Front(t *testing.T) {
+	dll := datastruct.NewDoubleLinkedList[int, string]()
+
+	t.Run("front of empty list", func(t *testing.T) {
+		_, _, ok := dll.Front()
+
+		assert.False(t, ok)
+	})
+
+	t.Run("front returns the head", func(t *testing.T) {
+		dll.InsertTail(1, "1")
+		dll.InsertTail(2, "2")
+
+		key, item, ok := dll.Front()
+
+		assert.True(t, ok)
+		assert.Equal(t, 1, key)
+		assert.Equal(t, "1", item)
+		assert.Equal(t, []int{1, 2}, dll.AllKeys())
+	})
+}
+
+func TestDoubleLinkedList_Iterators(t *testing.T) {
+	dll := datastruct.NewDoubleLinkedList[int, string]()
+
+	t.Run("empty list yields nothing", func(t *testing.T) {
+		assert.Empty(t, slices.Collect(dll.Keys()))
+		assert.Empty(t, slices.Collect(dll.Values()))
+	})
+
+	dll.InsertHead(3, "3")
+	dll.InsertHead(2, "2")
+	dll.InsertHead(1, "1")
+
+	t.Run("all and backward yield keys and items", func(t *testing.T) {
+		var keys []int
+		var items []string
+		for key, item := range dll.All() {
+			keys = append(keys, key)
+			items = append(items, item)
+		}
+		assert.Equal(t, []int{1, 2, 3}, keys)
+		assert.Equal(t, []string{"1", "2", "3"}, items)
+
+		keys, items = nil, nil
+		for key, item := range dll.Backward() {
+			keys = append(keys, key)
+			items = append(items, item)
+		}
+		assert.Equal(t, []int{3, 2, 1}, keys)
+		assert.Equal(t, []string{"3", "2", "1"}, items)
+	})
+
+	t.Run("keys and values match the slices", func(t *testing.T) {
+		assert.Equal(t, dll.AllKeys(), slices.Collect(dll.Keys()))
+		assert.Equal(t, dll.AllItems(), slices.Collect(dll.Values()))
+	})
+
+	t.Run("stops early", func(t *testing.T) {
+		var keys []int
+		for key := range dll.Keys() {
+			keys = append(keys, key)
+			if key == 2 {
+				break
+			}
+		}
+		assert.Equal(t, []int{1, 2}, keys)
+
+		keys = nil
+		for key := range dll.Backward() {
+			keys = append(keys, key)
+			break
+		}
+		assert.Equal(t, []int{3}, keys)
+	})
+}
+
+func newDoubleLinkedListOf(keys ...int) *datastruct.DoubleLinkedList[int, string] {
+	dll := datastruct.NewDoubleLinkedList[int, string]()
+	for _, key := range keys {
+		dll.InsertTail(key, strconv.Itoa(key))
+	}
+	return dll
+}
+
+func TestDoubleLinkedList_PushList(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		keys        []int
+		other       []int
+		front       bool
+		wantKeys    []int
+		wantReverse []int
+	}{
+		{desc: "push back", keys: []int{1, 2}, other: []int{3, 4}, wantKeys: []int{1, 2, 3, 4}, wantReverse: []int{4, 3, 2, 1}},
+		{desc: "push front", keys: []int{1, 2}, other: []int{3, 4}, front: true, wantKeys: []int{3, 4, 1, 2}, wantReverse: []int{2, 1, 4, 3}},
+		{desc: "push back onto empty", other: []int{3, 4}, wantKeys: []int{3, 4}, wantReverse: []int{4, 3}},
+		{desc: "push front onto empty", other: []int{3, 4}, front: true, wantKeys: []int{3, 4}, wantReverse: []int{4, 3}},
+		{desc: "push back empty", keys: []int{1, 2}, wantKeys: []int{1, 2}, wantReverse: []int{2, 1}},
+		{desc: "push front empty", keys: []int{1, 2}, front: true, wantKeys: []int{1, 2}, wantReverse: []int{2, 1}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			dll := newDoubleLinkedListOf(tC.keys...)
+			other := newDoubleLinkedListOf(tC.other...)
+
+			if tC.front {
+				dll.PushFrontList(other)
+			} else {
+				dll.PushBackList(other)
+			}
+
+			assert.Equal(t, tC.wantKeys, dll.AllKeys())
+			assert.Equal(t, tC.wantReverse, dll.AllKeysReverse())
+			assert.Empty(t, other.AllKeys())
+			assert.Empty(t, other.AllKeysReverse())
+		})
+	}
+}
+
+func TestDoubleLinkedList_PushList_MovesElements(t *testing.T) {
+	dll := newDoubleLinkedListOf(1)
+	other := datastruct.NewDoubleLinkedList[int, string]()
+	e, _ := other.TryInsertTail(2, "2")
+	other.InsertTail(3, "3")
+
+	dll.PushBackList(other)
+	e.MoveToFront()
+
+	assert.Equal(t, []int{2, 1, 3}, dll.AllKeys())
+	assert.Equal(t, []int{3, 1, 2}, dll.AllKeysReverse())
+	assert.Empty(t, other.AllKeys())
+}
+
+func TestDoubleLinkedList_Reverse(t *testing.T) {
+	testCases := []struct {
+		desc string
+		keys []int
+		want []int
+	}{
+		{desc: "empty", keys: nil, want: nil},
+		{desc: "one key", keys: []int{1}, want: []int{1}},
+		{desc: "several keys", keys: []int{1, 2, 3}, want: []int{3, 2, 1}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			dll := newDoubleLinkedListOf(tC.keys...)
+
+			dll.Reverse()
+
+			assert.Equal(t, tC.want, dll.AllKeys())
+			assert.Equal(t, tC.keys, dll.AllKeysReverse())
+		})
+	}
+}
+
+func TestDoubleLinkedList_SplitAt(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		i               int
+		wantKeys        []int
+		wantKeysReverse []int
+		wantRest        []int
+		wantRestReverse []int
+	}{
+		{desc: "split in the middle", i: 2, wantKeys: []int{1, 2}, wantKeysReverse: []int{2, 1}, wantRest: []int{3, 4}, wantRestReverse: []int{4, 3}},
+		{desc: "split at 0", i: 0, wantRest: []int{1, 2, 3, 4}, wantRestReverse: []int{4, 3, 2, 1}},
+		{desc: "split at the length", i: 4, wantKeys: []int{1, 2, 3, 4}, wantKeysReverse: []int{4, 3, 2, 1}},
+		{desc: "split past the length", i: 7, wantKeys: []int{1, 2, 3, 4}, wantKeysReverse: []int{4, 3, 2, 1}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			dll := newDoubleLinkedListOf(1, 2, 3, 4)
+
+			rest := dll.SplitAt(tC.i)
+
+			assert.Equal(t, tC.wantKeys, dll.AllKeys())
+			assert.Equal(t, tC.wantKeysReverse, dll.AllKeysReverse())
+			assert.Equal(t, tC.wantRest, rest.AllKeys())
+			assert.Equal(t, tC.wantRestReverse, rest.AllKeysReverse())
+		})
+	}
+}
+
+func TestDoubleLinkedList_SplitAt_MovesElements(t *testing.T) {
+	dll := datastruct.NewDoubleLinkedList[int, string]()
+	dll.InsertTail(1, "1")
+	dll.InsertTail(2, "2")
+	e, _ := dll.TryInsertTail(3, "3")
+
+	rest := dll.SplitAt(1)
+	e.MoveToFront()
+
+	assert.Equal(t, []int{1}, dll.AllKeys())
+	assert.Equal(t, []int{3, 2}, rest.AllKeys())
+	assert.Equal(t, []int{2, 3}, rest.AllKeysReverse())
+}
+
+func TestDoubleLinkedList_Len(t *testing.T) {
+	dll := datastruct.NewDoubleLinkedList[int, string]()
+	assert.Equal(t, 0, dll.Len())
+
+	dll.InsertHead(1, "1")
+	dll.InsertHead(2, "2")
+	dll.InsertAfter(1, 3, "3")
+	assert.Equal(t, 3, dll.Len())
+
+	dll.Delete(2)
+	assert.Equal(t, 2, dll.Len())
+	dll.Delete(7)
+	assert.Equal(t, 2, dll.Len())
+
+	dll.Reverse()
+	dll.Sort(func(a, b string) bool { return a < b })
+	assert.Equal(t, 2, dll.Len())
+}
+
+func TestDoubleLinkedList_FrontBack(t *testing.T) {
+	dll := datastruct.NewDoubleLinkedList[int, string]()
+
+	_, _, ok := dll.Front()
+	assert.False(t, ok)
+	_, _, ok = dll.Back()
+	assert.False(t, ok)
+
+	dll = newDoubleLinkedListOf(1, 2, 3)
+
+	key, item, ok := dll.Front()
+	assert.True(t, ok)
+	assert.Equal(t, 1, key)
+	assert.Equal(t, "1", item)
+
+	key, item, ok = dll.Back()
+	assert.True(t, ok)
+	assert.Equal(t, 3, key)
+	assert.Equal(t, "3", item)
+}
+
+func TestDoubleLinkedList_At(t *testing.T) {
+	dll := newDoubleLinkedListOf(1, 2, 3, 4, 5)
+
+	for i := 0; i < 5; i++ {
+		key, item, ok := dll.At(i)
+
+		assert.True(t, ok)
+		assert.Equal(t, i+1, key)
+		assert.Equal(t, strconv.Itoa(i+1), item)
+	}
+
+	_, _, ok := dll.At(-1)
+	assert.False(t, ok)
+	_, _, ok = dll.At(5)
+	assert.False(t, ok)
+}
+
+func TestDoubleLinkedList_InsertAt(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		i        int
+		wantErr  error
+		wantKeys []int
+	}{
+		{desc: "insert at the head", i: 0, wantKeys: []int{9, 1, 2, 3}},
+		{desc: "insert in the middle", i: 1, wantKeys: []int{1, 9, 2, 3}},
+		{desc: "insert before the tail", i: 2, wantKeys: []int{1, 2, 9, 3}},
+		{desc: "insert at the tail", i: 3, wantKeys: []int{1, 2, 3, 9}},
+		{desc: "insert past the tail", i: 4, wantErr: errors.New("index out of range"), wantKeys: []int{1, 2, 3}},
+		{desc: "insert at a negative index", i: -1, wantErr: errors.New("index out of range"), wantKeys: []int{1, 2, 3}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			dll := newDoubleLinkedListOf(1, 2, 3)
+
+			e, err := dll.TryInsertAt(tC.i, 9, "9")
+
+			assert.Equal(t, tC.wantErr, err)
+			assert.Equal(t, tC.wantErr == nil, e != nil)
+			assert.Equal(t, tC.wantKeys, dll.AllKeys())
+			assert.Equal(t, len(tC.wantKeys), dll.Len())
+			assert.Equal(t, reverse(dll.AllKeys()), dll.AllKeysReverse())
+		})
+	}
+}
+
+func TestDoubleLinkedList_RemoveAt(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		i        int
+		wantOK   bool
+		wantKey  int
+		wantKeys []int
+	}{
+		{desc: "remove the head", i: 0, wantOK: true, wantKey: 1, wantKeys: []int{2, 3, 4}},
+		{desc: "remove from the middle", i: 1, wantOK: true, wantKey: 2, wantKeys: []int{1, 3, 4}},
+		{desc: "remove nearer the tail", i: 2, wantOK: true, wantKey: 3, wantKeys: []int{1, 2, 4}},
+		{desc: "remove the tail", i: 3, wantOK: true, wantKey: 4, wantKeys: []int{1, 2, 3}},
+		{desc: "remove past the tail", i: 4, wantOK: false, wantKeys: []int{1, 2, 3, 4}},
+		{desc: "remove at a negative index", i: -1, wantOK: false, wantKeys: []int{1, 2, 3, 4}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			dll := newDoubleLinkedListOf(1, 2, 3, 4)
+
+			key, item, ok := dll.RemoveAt(tC.i)
+
+			assert.Equal(t, tC.wantOK, ok)
+			if ok {
+				assert.Equal(t, tC.wantKey, key)
+				assert.Equal(t, strconv.Itoa(tC.wantKey), item)
+			}
+			assert.Equal(t, tC.wantKeys, dll.AllKeys())
+			assert.Equal(t, len(tC.wantKeys), dll.Len())
+			assert.Equal(t, reverse(dll.AllKeys()), dll.AllKeysReverse())
+
+			// the tail must still be right
+			backKey, _, _ := dll.Back()
+			assert.Equal(t, tC.wantKeys[len(tC.wantKeys)-1], backKey)
+		})
+	}
+}
+
+func TestDoubleLinkedList_WithIndex(t *testing.T) {
+	t.Run("indexes the nodes already in the list", func(t *testing.T) {
+		dll := newDoubleLinkedListOf(1, 2, 3).WithIndex()
+
+		ok, item := dll.Search(2)
+		assert.True(t, ok)
+		assert.Equal(t, "2", item)
+
+		assert.True(t, dll.InsertAfter(2, 4, "4"))
+		assert.True(t, dll.Delete(1))
+		assert.Equal(t, []int{2, 4, 3}, dll.AllKeys())
+		assert.Equal(t, []int{3, 4, 2}, dll.AllKeysReverse())
+	})
+
+	t.Run("upserts rather than allowing duplicates", func(t *testing.T) {
+		dll := datastruct.NewDoubleLinkedList[int, string]().WithIndex()
+		dll.InsertTail(1, "1")
+		dll.InsertTail(1, "one")
+
+		assert.Equal(t, []int{1}, dll.AllKeys())
+		assert.Equal(t, []string{"one"}, dll.SearchAll(1))
+		assert.Equal(t, 1, dll.DeleteAll(1))
+		assert.Equal(t, 0, dll.Len())
+	})
+
+	t.Run("keeps rejecting duplicates", func(t *testing.T) {
+		dll := datastruct.NewDoubleLinkedList[int, string]().
+			WithDuplicatePolicy(datastruct.RejectDuplicates).
+			WithIndex()
+		dll.InsertTail(1, "1")
+
+		_, err := dll.TryInsertHead(1, "one")
+		assert.ErrorIs(t, err, datastruct.ErrDuplicateKey)
+	})
+
+	t.Run("removed keys can be inserted again", func(t *testing.T) {
+		dll := datastruct.NewDoubleLinkedList[int, string]().
+			WithDuplicatePolicy(datastruct.RejectDuplicates).
+			WithIndex()
+		e, _ := dll.TryInsertTail(1, "1")
+		dll.InsertTail(2, "2")
+		dll.InsertTail(3, "3")
+
+		e.Remove()
+		dll.DeleteHead()
+		dll.RemoveAt(0)
+
+		for _, key := range []int{1, 2, 3} {
+			_, err := dll.TryInsertTail(key, "")
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, []int{1, 2, 3}, dll.AllKeys())
+	})
+
+	t.Run("split moves the index of the rest", func(t *testing.T) {
+		dll := newDoubleLinkedListOf(1, 2, 3, 4).WithIndex()
+
+		rest := dll.SplitAt(2)
+
+		ok, _ := dll.Search(3)
+		assert.False(t, ok)
+		ok, item := rest.Search(3)
+		assert.True(t, ok)
+		assert.Equal(t, "3", item)
+
+		// rest is indexed too, so it upserts
+		rest.InsertTail(4, "four")
+		assert.Equal(t, []string{"3", "four"}, rest.AllItems())
+	})
+
+	t.Run("push list moves the index of the other list", func(t *testing.T) {
+		dll := newDoubleLinkedListOf(1, 2).WithIndex()
+		other := newDoubleLinkedListOf(3, 4).WithIndex()
+
+		dll.PushBackList(other)
+
+		ok, _ := dll.Search(4)
+		assert.True(t, ok)
+		ok, _ = other.Search(4)
+		assert.False(t, ok)
+		assert.True(t, dll.InsertAfter(3, 5, "5"))
+		assert.Equal(t, []int{1, 2, 3, 5, 4}, dll.AllKeys())
+	})
+
+	t.Run("push list upserts keys already in the list", func(t *testing.T) {
+		dll := newDoubleLinkedListOf(1, 2).WithIndex()
+		other := datastruct.NewDoubleLinkedList[int, string]().WithIndex()
+		other.InsertTail(1, "one")
+		other.InsertTail(3, "3")
+
+		dll.PushBackList(other)
+
+		assert.Equal(t, []int{1, 2, 3}, dll.AllKeys())
+		assert.Equal(t, []int{3, 2, 1}, dll.AllKeysReverse())
+		assert.Equal(t, []string{"one", "2", "3"}, dll.AllItems())
+		assert.Equal(t, 3, dll.Len())
+		assert.Empty(t, other.AllKeys())
+		assert.Equal(t, 0, other.Len())
+
+		assert.True(t, dll.Delete(1))
+		ok, _ := dll.Search(1)
+		assert.False(t, ok)
+		assert.Equal(t, []int{2, 3}, dll.AllKeys())
+	})
+
+	t.Run("push list leaves rejected keys in the other list", func(t *testing.T) {
+		dll := newDoubleLinkedListOf(1, 2).
+			WithDuplicatePolicy(datastruct.RejectDuplicates).
+			WithIndex()
+		other := datastruct.NewDoubleLinkedList[int, string]()
+		e, _ := other.TryInsertTail(2, "two")
+		other.InsertTail(3, "3")
+		other.InsertTail(3, "three")
+
+		dll.PushFrontList(other)
+
+		assert.Equal(t, []int{3, 1, 2}, dll.AllKeys())
+		assert.Equal(t, []int{2, 1, 3}, dll.AllKeysReverse())
+		assert.Equal(t, []string{"3", "1", "2"}, dll.AllItems())
+		assert.Equal(t, 3, dll.Len())
+		assert.Equal(t, []int{2, 3}, other.AllKeys())
+		assert.Equal(t, []int{3, 2}, other.AllKeysReverse())
+		assert.Equal(t, 2, other.Len())
+
+		// the rejected node's element still belongs to the other list
+		assert.True(t, e.Remove())
+		assert.Equal(t, []int{3}, other.AllKeys())
+		assert.Equal(t, []int{3, 1, 2}, dll.AllKeys())
+	})
+
+	t.Run("merge sorted upserts keys already in the list", func(t *testing.T) {
+		dll := newDoubleLinkedListOf(1, 3).WithIndex()
+		other := newDoubleLinkedListOf(2, 3)
+
+		dll.MergeSorted(other, func(a, b string) bool { return a < b })
+
+		assert.Equal(t, []int{1, 2, 3}, dll.AllKeys())
+		assert.Equal(t, []int{3, 2, 1}, dll.AllKeysReverse())
+		assert.Equal(t, 3, dll.Len())
+		assert.Equal(t, 0, other.Len())
+		assert.True(t, dll.Delete(3))
+		ok, _ := dll.Search(3)
+		assert.False(t, ok)
+	})
+
+	t.Run("indexing a list with duplicates keeps the first node of each key", func(t *testing.T) {
+		dll := newDoubleLinkedListOf(1, 2, 1)
+		dll.InsertTail(2, "two")
+
+		dll.WithIndex()
+
+		assert.Equal(t, []int{1, 2}, dll.AllKeys())
+		assert.Equal(t, []int{2, 1}, dll.AllKeysReverse())
+		assert.Equal(t, []string{"1", "two"}, dll.AllItems())
+		assert.Equal(t, 2, dll.Len())
+	})
+}
+
+// BenchmarkDoubleLinkedList_PushList moves all of one list onto the other and back, which should take the same time
+// for every size as long as no elements have been handed out.
+func BenchmarkDoubleLinkedList_PushList(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run("back/"+strconv.Itoa(size), func(b *testing.B) {
+			dll, other := newDoubleLinkedListOf(make([]int, size)...), newDoubleLinkedListOf(make([]int, size)...)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				dll.PushBackList(other)
+				dll, other = other, dll
+			}
+		})
+		b.Run("front/"+strconv.Itoa(size), func(b *testing.B) {
+			dll, other := newDoubleLinkedListOf(make([]int, size)...), newDoubleLinkedListOf(make([]int, size)...)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				dll.PushFrontList(other)
+				dll, other = other, dll
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package datastruct
+
+import "errors"
+
+// DuplicatePolicy decides what the linked lists do when a key that is already in the list is inserted. A list
+// without an index only applies it to inserts, the nodes of lists moved in with PushBackList, PushFrontList or
+// MergeSorted are taken as they are. An indexed DoubleLinkedList applies it to those nodes too, as it can only
+// hold each key once.
+type DuplicatePolicy int8
+
+const (
+	// AllowDuplicates inserts the key again. Search and Delete act on the match nearest the head, SearchAll and
+	// DeleteAll on every match.
+	AllowDuplicates DuplicatePolicy = iota
+	// RejectDuplicates leaves the list as it is. The Try inserts return ErrDuplicateKey and InsertAfter and
+	// InsertAt return false.
+	RejectDuplicates
+	// UpsertDuplicates replaces the item of the key already in the list, leaving it where it is.
+	UpsertDuplicates
+)
+
+var ErrDuplicateKey = errors.New("key already exists")
@@ -0,0 +1,137 @@
+package datastruct_test
+
+import (
+	"testing"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSingleLinkedList_DuplicatePolicy(t *testing.T) {
+	t.Run("allow searches and deletes from the head", func(t *testing.T) {
+		sll := datastruct.NewSingleLinkedList[int, string]()
+		sll.InsertHead(1, "c")
+		sll.InsertHead(1, "b")
+		sll.InsertHead(2, "2")
+		require.True(t, sll.InsertAfter(2, 1, "a"))
+
+		ok, val := sll.Search(1)
+		assert.True(t, ok)
+		assert.Equal(t, "a", val)
+		assert.Equal(t, []string{"a", "b", "c"}, sll.SearchAll(1))
+
+		assert.True(t, sll.Delete(1))
+		assert.Equal(t, []string{"2", "b", "c"}, sll.AllItems())
+
+		assert.Equal(t, 2, sll.DeleteAll(1))
+		assert.Equal(t, 0, sll.DeleteAll(1))
+		assert.Equal(t, []int{2}, sll.AllKeys())
+		assert.Equal(t, 1, sll.Len())
+		assert.Empty(t, sll.SearchAll(1))
+	})
+
+	t.Run("reject fails every insert of a key in the list", func(t *testing.T) {
+		sll := datastruct.NewSingleLinkedList[int, string]().WithDuplicatePolicy(datastruct.RejectDuplicates)
+		require.NoError(t, sll.TryInsertHead(1, "1"))
+		require.NoError(t, sll.TryInsertAfter(1, 2, "2"))
+
+		assert.ErrorIs(t, sll.TryInsertHead(1, "x"), datastruct.ErrDuplicateKey)
+		assert.ErrorIs(t, sll.TryInsertAfter(1, 2, "x"), datastruct.ErrDuplicateKey)
+		assert.ErrorIs(t, sll.TryInsertAt(2, 1, "x"), datastruct.ErrDuplicateKey)
+		sll.InsertHead(1, "x")
+		assert.False(t, sll.InsertAfter(1, 2, "x"))
+		assert.False(t, sll.InsertAt(2, 1, "x"))
+
+		assert.Equal(t, []int{1, 2}, sll.AllKeys())
+		assert.Equal(t, []string{"1", "2"}, sll.AllItems())
+		assert.Equal(t, 2, sll.Len())
+	})
+
+	t.Run("upsert replaces the item in place", func(t *testing.T) {
+		sll := datastruct.NewSingleLinkedList[int, string]().WithDuplicatePolicy(datastruct.UpsertDuplicates)
+		sll.InsertHead(2, "2")
+		sll.InsertHead(1, "1")
+
+		sll.InsertHead(2, "two")
+		require.True(t, sll.InsertAt(2, 1, "one"))
+		require.True(t, sll.InsertAfter(2, 3, "3"))
+
+		assert.Equal(t, []int{1, 2, 3}, sll.AllKeys())
+		assert.Equal(t, []string{"one", "two", "3"}, sll.AllItems())
+		assert.Equal(t, 3, sll.Len())
+	})
+}
+
+func TestDoubleLinkedList_DuplicatePolicy(t *testing.T) {
+	t.Run("allow searches and deletes from the head", func(t *testing.T) {
+		dll := datastruct.NewDoubleLinkedList[int, string]()
+		dll.InsertTail(1, "a")
+		dll.InsertTail(2, "2")
+		dll.InsertTail(1, "b")
+		dll.InsertTail(1, "c")
+
+		ok, val := dll.Search(1)
+		assert.True(t, ok)
+		assert.Equal(t, "a", val)
+		assert.Equal(t, []string{"a", "b", "c"}, dll.SearchAll(1))
+
+		assert.True(t, dll.Delete(1))
+		assert.Equal(t, []string{"2", "b", "c"}, dll.AllItems())
+
+		require.True(t, dll.InsertAfter(1, 3, "3"))
+		assert.Equal(t, []int{2, 1, 3, 1}, dll.AllKeys())
+
+		assert.Equal(t, 2, dll.DeleteAll(1))
+		assert.Equal(t, []int{2, 3}, dll.AllKeys())
+		assert.Equal(t, []int{3, 2}, dll.AllKeysReverse())
+		assert.Equal(t, 2, dll.Len())
+
+		ok, _ = dll.Search(1)
+		assert.False(t, ok)
+	})
+
+	t.Run("reject fails every insert of a key in the list", func(t *testing.T) {
+		dll := datastruct.NewDoubleLinkedList[int, string]().WithDuplicatePolicy(datastruct.RejectDuplicates)
+		e, err := dll.TryInsertTail(1, "1")
+		require.NoError(t, err)
+		_, err = dll.TryInsertTail(2, "2")
+		require.NoError(t, err)
+
+		_, err = dll.TryInsertHead(1, "x")
+		assert.ErrorIs(t, err, datastruct.ErrDuplicateKey)
+		_, err = dll.TryInsertTail(2, "x")
+		assert.ErrorIs(t, err, datastruct.ErrDuplicateKey)
+		_, err = dll.TryInsertAt(1, 2, "x")
+		assert.ErrorIs(t, err, datastruct.ErrDuplicateKey)
+		_, err = dll.TryInsertAfter(1, 2, "x")
+		assert.ErrorIs(t, err, datastruct.ErrDuplicateKey)
+		_, err = e.InsertAfter(1, "x")
+		assert.ErrorIs(t, err, datastruct.ErrDuplicateKey)
+		dll.InsertHead(1, "x")
+		dll.InsertTail(2, "x")
+		assert.False(t, dll.InsertAt(1, 2, "x"))
+		assert.False(t, dll.InsertAfter(1, 2, "x"))
+
+		assert.Equal(t, []int{1, 2}, dll.AllKeys())
+		assert.Equal(t, []string{"1", "2"}, dll.AllItems())
+		assert.Equal(t, 2, dll.Len())
+	})
+
+	t.Run("upsert replaces the item in place", func(t *testing.T) {
+		dll := datastruct.NewDoubleLinkedList[int, string]().WithDuplicatePolicy(datastruct.UpsertDuplicates)
+		e1, _ := dll.TryInsertTail(1, "1")
+		dll.InsertTail(2, "2")
+
+		e, err := dll.TryInsertHead(2, "two")
+		require.NoError(t, err)
+		assert.Equal(t, 2, e.Key())
+		e, err = e1.InsertBefore(1, "one")
+		require.NoError(t, err)
+		assert.Same(t, e1, e)
+
+		assert.Equal(t, []int{1, 2}, dll.AllKeys())
+		assert.Equal(t, []string{"one", "two"}, dll.AllItems())
+		assert.Equal(t, 2, dll.Len())
+	})
+}
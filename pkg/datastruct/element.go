@@ -0,0 +1,86 @@
+package datastruct
+
+import "errors"
+
+// Element is a handle to a key and item in a DoubleLinkedList, so that the list can be edited around it without
+// searching for the key. All of its operations are O(1), apart from inserts into a list that does not allow
+// duplicates, which have to search for the key. Once the element is removed from its list, Next and Prev return
+// nil, inserts fail and the other operations do nothing. Elements are only made for the nodes they are asked for,
+// by FrontElement, BackElement, the Try inserts and Next and Prev, so a list that is used without them pays nothing.
+type Element[K comparable, V any] struct {
+	list *DoubleLinkedList[K, V]
+	node *doublyNode[K, V]
+}
+
+func (e *Element[K, V]) Key() K {
+	return e.node.Key
+}
+
+func (e *Element[K, V]) Item() V {
+	return e.node.Item
+}
+
+func (e *Element[K, V]) SetItem(item V) {
+	e.node.Item = item
+}
+
+// Next returns the element after e, or nil if e is the tail.
+func (e *Element[K, V]) Next() *Element[K, V] {
+	if e.list == nil || e.node.Next == nil {
+		return nil
+	}
+	return e.list.element(e.node.Next)
+}
+
+// Prev returns the element before e, or nil if e is the head.
+func (e *Element[K, V]) Prev() *Element[K, V] {
+	if e.list == nil || e.node.Previous == nil {
+		return nil
+	}
+	return e.list.element(e.node.Previous)
+}
+
+// InsertBefore adds the key and item just before e, returning the element that holds them.
+func (e *Element[K, V]) InsertBefore(key K, item V) (*Element[K, V], error) {
+	if e.list == nil {
+		return nil, errors.New("element has been removed")
+	}
+	return e.list.elementOf(e.list.insert(key, item, func() *doublyNode[K, V] {
+		return e.list.insertBefore(e.node, key, item)
+	}))
+}
+
+// InsertAfter adds the key and item just after e, returning the element that holds them.
+func (e *Element[K, V]) InsertAfter(key K, item V) (*Element[K, V], error) {
+	if e.list == nil {
+		return nil, errors.New("element has been removed")
+	}
+	return e.list.elementOf(e.list.insert(key, item, func() *doublyNode[K, V] {
+		return e.list.insertAfter(e.node, key, item)
+	}))
+}
+
+// Remove takes e out of its list, returning false if it had already been removed.
+func (e *Element[K, V]) Remove() bool {
+	if e.list == nil {
+		return false
+	}
+	e.list.remove(e.node)
+	return true
+}
+
+func (e *Element[K, V]) MoveToFront() {
+	if e.list == nil || e.node == e.list.head {
+		return
+	}
+	e.list.unlink(e.node)
+	e.list.pushFront(e.node)
+}
+
+func (e *Element[K, V]) MoveToBack() {
+	if e.list == nil || e.node == e.list.tail {
+		return
+	}
+	e.list.unlink(e.node)
+	e.list.pushBack(e.node)
+}
@@ -0,0 +1,198 @@
+package datastruct_test
+
+import (
+	"testing"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElement_NextPrev(t *testing.T) {
+	dll := datastruct.NewDoubleLinkedList[int, string]()
+	assert.Nil(t, dll.FrontElement())
+	assert.Nil(t, dll.BackElement())
+
+	e2, _ := dll.TryInsertTail(2, "2")
+	e1, _ := dll.TryInsertHead(1, "1")
+	e3, _ := dll.TryInsertTail(3, "3")
+
+	assert.Same(t, e1, dll.FrontElement())
+	assert.Same(t, e3, dll.BackElement())
+	assert.Same(t, e2, e1.Next())
+	assert.Same(t, e3, e2.Next())
+	assert.Nil(t, e3.Next())
+	assert.Same(t, e2, e3.Prev())
+	assert.Same(t, e1, e2.Prev())
+	assert.Nil(t, e1.Prev())
+
+	assert.Equal(t, 2, e2.Key())
+	assert.Equal(t, "2", e2.Item())
+	e2.SetItem("two")
+	assert.Equal(t, []string{"1", "two", "3"}, dll.AllItems())
+}
+
+func TestElement_OnlyCreatedWhenAskedFor(t *testing.T) {
+	dll := datastruct.NewDoubleLinkedList[int, string]()
+	dll.InsertTail(1, "1")
+
+	// the node is the only allocation, no element is made for it
+	allocs := testing.AllocsPerRun(100, func() {
+		dll.InsertTail(2, "2")
+		dll.InsertHead(0, "0")
+		dll.InsertAt(1, 3, "3")
+		dll.InsertAfter(1, 4, "4")
+	})
+	assert.Equal(t, 4.0, allocs)
+
+	allocs = testing.AllocsPerRun(100, func() {
+		dll.TryInsertTail(2, "2")
+	})
+	assert.Equal(t, 2.0, allocs)
+}
+
+func TestElement_Insert(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		insert      func(e *datastruct.Element[int, string]) (*datastruct.Element[int, string], error)
+		wantKeys    []int
+		wantReverse []int
+	}{
+		{
+			desc: "insert before the head",
+			insert: func(e *datastruct.Element[int, string]) (*datastruct.Element[int, string], error) {
+				return e.InsertBefore(0, "0")
+			},
+			wantKeys:    []int{0, 1, 2},
+			wantReverse: []int{2, 1, 0},
+		},
+		{
+			desc: "insert after the head",
+			insert: func(e *datastruct.Element[int, string]) (*datastruct.Element[int, string], error) {
+				return e.InsertAfter(9, "9")
+			},
+			wantKeys:    []int{1, 9, 2},
+			wantReverse: []int{2, 9, 1},
+		},
+		{
+			desc: "insert after the tail",
+			insert: func(e *datastruct.Element[int, string]) (*datastruct.Element[int, string], error) {
+				return e.Next().InsertAfter(3, "3")
+			},
+			wantKeys:    []int{1, 2, 3},
+			wantReverse: []int{3, 2, 1},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			dll := datastruct.NewDoubleLinkedList[int, string]()
+			head, _ := dll.TryInsertTail(1, "1")
+			dll.InsertTail(2, "2")
+
+			e, err := tC.insert(head)
+
+			require.NoError(t, err)
+			require.NotNil(t, e)
+			assert.Equal(t, tC.wantKeys, dll.AllKeys())
+			assert.Equal(t, 3, dll.Len())
+			assert.Equal(t, tC.wantReverse, dll.AllKeysReverse())
+		})
+	}
+}
+
+func TestElement_Remove(t *testing.T) {
+	dll := datastruct.NewDoubleLinkedList[int, string]()
+	e1, _ := dll.TryInsertTail(1, "1")
+	e2, _ := dll.TryInsertTail(2, "2")
+	e3, _ := dll.TryInsertTail(3, "3")
+
+	t.Run("remove from the middle", func(t *testing.T) {
+		assert.True(t, e2.Remove())
+
+		assert.Equal(t, []int{1, 3}, dll.AllKeys())
+		assert.Equal(t, []int{3, 1}, dll.AllKeysReverse())
+		assert.Same(t, e3, e1.Next())
+	})
+
+	t.Run("removed element is detached", func(t *testing.T) {
+		assert.False(t, e2.Remove())
+		assert.Nil(t, e2.Next())
+		assert.Nil(t, e2.Prev())
+		_, err := e2.InsertAfter(4, "4")
+		assert.EqualError(t, err, "element has been removed")
+		e2.MoveToFront()
+
+		assert.Equal(t, []int{1, 3}, dll.AllKeys())
+	})
+
+	t.Run("element deleted by key is detached", func(t *testing.T) {
+		assert.True(t, dll.Delete(3))
+
+		assert.False(t, e3.Remove())
+		assert.Equal(t, []int{1}, dll.AllKeys())
+	})
+
+	t.Run("remove the last element", func(t *testing.T) {
+		assert.True(t, e1.Remove())
+
+		assert.Empty(t, dll.AllKeys())
+		assert.Empty(t, dll.AllKeysReverse())
+		assert.Nil(t, dll.FrontElement())
+		assert.Equal(t, 0, dll.Len())
+	})
+}
+
+func TestElement_Move(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		move        func(es []*datastruct.Element[int, string])
+		wantKeys    []int
+		wantReverse []int
+	}{
+		{
+			desc:        "move the tail to the front",
+			move:        func(es []*datastruct.Element[int, string]) { es[2].MoveToFront() },
+			wantKeys:    []int{3, 1, 2},
+			wantReverse: []int{2, 1, 3},
+		},
+		{
+			desc:        "move the middle to the front",
+			move:        func(es []*datastruct.Element[int, string]) { es[1].MoveToFront() },
+			wantKeys:    []int{2, 1, 3},
+			wantReverse: []int{3, 1, 2},
+		},
+		{
+			desc:        "move the head to the front",
+			move:        func(es []*datastruct.Element[int, string]) { es[0].MoveToFront() },
+			wantKeys:    []int{1, 2, 3},
+			wantReverse: []int{3, 2, 1},
+		},
+		{
+			desc:        "move the head to the back",
+			move:        func(es []*datastruct.Element[int, string]) { es[0].MoveToBack() },
+			wantKeys:    []int{2, 3, 1},
+			wantReverse: []int{1, 3, 2},
+		},
+		{
+			desc:        "move the middle to the back",
+			move:        func(es []*datastruct.Element[int, string]) { es[1].MoveToBack() },
+			wantKeys:    []int{1, 3, 2},
+			wantReverse: []int{2, 3, 1},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			dll := datastruct.NewDoubleLinkedList[int, string]()
+			var es []*datastruct.Element[int, string]
+			for i := 1; i <= 3; i++ {
+				e, _ := dll.TryInsertTail(i, "")
+				es = append(es, e)
+			}
+
+			tC.move(es)
+
+			assert.Equal(t, tC.wantKeys, dll.AllKeys())
+			assert.Equal(t, tC.wantReverse, dll.AllKeysReverse())
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package datastruct
+
+import "iter"
+
+// LinkedHashMap is a map that iterates in the order its keys were first put in. It is an indexed DoubleLinkedList,
+// so everything apart from iterating is O(1).
+type LinkedHashMap[K comparable, V any] struct {
+	list *DoubleLinkedList[K, V]
+}
+
+func NewLinkedHashMap[K comparable, V any]() *LinkedHashMap[K, V] {
+	return &LinkedHashMap[K, V]{
+		list: NewDoubleLinkedList[K, V]().WithIndex(),
+	}
+}
+
+// Put sets the item for key. A new key goes at the end, a key already in the map keeps its place.
+func (m *LinkedHashMap[K, V]) Put(key K, item V) {
+	// the list upserts, so the insert cannot fail
+	m.list.InsertTail(key, item)
+}
+
+func (m *LinkedHashMap[K, V]) Get(key K) (V, bool) {
+	ok, item := m.list.Search(key)
+	return item, ok
+}
+
+func (m *LinkedHashMap[K, V]) Delete(key K) bool {
+	return m.list.Delete(key)
+}
+
+// MoveToEnd moves key to the end of the iteration order, as if it had just been put in, returning false if the key
+// is not in the map. Moving keys to the end when they are used keeps the least recently used key at the front.
+func (m *LinkedHashMap[K, V]) MoveToEnd(key K) bool {
+	node := m.list.findNode(key)
+	if node == nil {
+		return false
+	}
+	if node != m.list.tail {
+		m.list.unlink(node)
+		m.list.pushBack(node)
+	}
+	return true
+}
+
+// Front returns the key and item at the front of the iteration order, or false if the map is empty.
+func (m *LinkedHashMap[K, V]) Front() (K, V, bool) {
+	return m.list.Front()
+}
+
+func (m *LinkedHashMap[K, V]) Len() int {
+	return m.list.Len()
+}
+
+// All returns an iterator over the keys and items in order.
+func (m *LinkedHashMap[K, V]) All() iter.Seq2[K, V] {
+	return m.list.All()
+}
+
+// Backward returns an iterator over the keys and items in reverse order.
+func (m *LinkedHashMap[K, V]) Backward() iter.Seq2[K, V] {
+	return m.list.Backward()
+}
+
+func (m *LinkedHashMap[K, V]) Keys() iter.Seq[K] {
+	return m.list.Keys()
+}
+
+func (m *LinkedHashMap[K, V]) Values() iter.Seq[V] {
+	return m.list.Values()
+}
@@ -0,0 +1,66 @@
+package datastruct_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkedHashMap(t *testing.T) {
+	m := datastruct.NewLinkedHashMap[string, int]()
+
+	t.Run("empty map", func(t *testing.T) {
+		_, ok := m.Get("a")
+		assert.False(t, ok)
+		_, _, ok = m.Front()
+		assert.False(t, ok)
+		assert.Equal(t, 0, m.Len())
+		assert.False(t, m.MoveToEnd("a"))
+	})
+
+	t.Run("put keeps the order keys were first put in", func(t *testing.T) {
+		m.Put("a", 1)
+		m.Put("b", 2)
+		m.Put("c", 3)
+		m.Put("a", 10)
+
+		item, ok := m.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 10, item)
+		assert.Equal(t, []string{"a", "b", "c"}, slices.Collect(m.Keys()))
+		assert.Equal(t, []int{10, 2, 3}, slices.Collect(m.Values()))
+		assert.Equal(t, 3, m.Len())
+	})
+
+	t.Run("move to end", func(t *testing.T) {
+		assert.True(t, m.MoveToEnd("a"))
+		assert.True(t, m.MoveToEnd("a"))
+
+		assert.Equal(t, []string{"b", "c", "a"}, slices.Collect(m.Keys()))
+		var backward []string
+		for key := range m.Backward() {
+			backward = append(backward, key)
+		}
+		assert.Equal(t, []string{"a", "c", "b"}, backward)
+
+		key, item, ok := m.Front()
+		assert.True(t, ok)
+		assert.Equal(t, "b", key)
+		assert.Equal(t, 2, item)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.True(t, m.Delete("c"))
+		assert.False(t, m.Delete("c"))
+
+		_, ok := m.Get("c")
+		assert.False(t, ok)
+		assert.Equal(t, []string{"b", "a"}, slices.Collect(m.Keys()))
+		assert.Equal(t, 2, m.Len())
+
+		m.Put("c", 30)
+		assert.Equal(t, []string{"b", "a", "c"}, slices.Collect(m.Keys()))
+	})
+}
@@ -0,0 +1,154 @@
+package datastruct_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sortable is implemented by both lists, so that the same properties can be checked on each.
+type sortable[L any] interface {
+	InsertTail(key int, item int)
+	Sort(less func(a, b int) bool)
+	MergeSorted(other L, less func(a, b int) bool)
+	AllKeys() []int
+	AllItems() []int
+	Len() int
+}
+
+type entry struct {
+	key  int
+	item int
+}
+
+func lessItem(a, b int) bool {
+	return a < b
+}
+
+// randomEntries returns entries with unique keys, in order, and items from a small range so that there are
+// plenty of equal items to check that sorting is stable.
+func randomEntries(r *rand.Rand, start int) []entry {
+	entries := make([]entry, r.Intn(50))
+	for i := range entries {
+		entries[i] = entry{key: start + i, item: r.Intn(10)}
+	}
+	return entries
+}
+
+func sortedEntries(entries []entry) []entry {
+	sorted := append([]entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].item < sorted[j].item })
+	return sorted
+}
+
+func assertEntries(t *testing.T, want []entry, keys []int, items []int) {
+	t.Helper()
+	require.Len(t, keys, len(want))
+	for i, e := range want {
+		assert.Equal(t, e.key, keys[i])
+		assert.Equal(t, e.item, items[i])
+	}
+}
+
+// checkSortProperties checks Sort and MergeSorted on lists from newList, and calls check on every list sorted or
+// merged for checks specific to the list type.
+func checkSortProperties[L sortable[L]](t *testing.T, newList func() L, check func(t *testing.T, l L)) {
+	r := rand.New(rand.NewSource(1))
+
+	t.Run("sort matches sort.SliceStable", func(t *testing.T) {
+		for i := 0; i < 200; i++ {
+			entries := randomEntries(r, 0)
+			l := newList()
+			for _, e := range entries {
+				l.InsertTail(e.key, e.item)
+			}
+
+			l.Sort(lessItem)
+
+			assertEntries(t, sortedEntries(entries), l.AllKeys(), l.AllItems())
+			assert.Equal(t, len(entries), l.Len())
+			check(t, l)
+		}
+	})
+
+	t.Run("merge sorted matches sort.SliceStable of both lists", func(t *testing.T) {
+		for i := 0; i < 200; i++ {
+			a := sortedEntries(randomEntries(r, 0))
+			b := sortedEntries(randomEntries(r, 100))
+			la, lb := newList(), newList()
+			for _, e := range a {
+				la.InsertTail(e.key, e.item)
+			}
+			for _, e := range b {
+				lb.InsertTail(e.key, e.item)
+			}
+
+			la.MergeSorted(lb, lessItem)
+
+			assertEntries(t, sortedEntries(append(a, b...)), la.AllKeys(), la.AllItems())
+			assert.Empty(t, lb.AllKeys())
+			assert.Equal(t, len(a)+len(b), la.Len())
+			assert.Equal(t, 0, lb.Len())
+			check(t, la)
+		}
+	})
+}
+
+// singleSortable and doubleSortable give both lists the same method set. The single list has no InsertTail, so
+// it pushes a list of one node instead.
+type singleSortable struct {
+	*datastruct.SingleLinkedList[int, int]
+}
+
+func (l singleSortable) InsertTail(key int, item int) {
+	l.PushBackList(singleOf(key, item))
+}
+
+func (l singleSortable) MergeSorted(other singleSortable, less func(a, b int) bool) {
+	l.SingleLinkedList.MergeSorted(other.SingleLinkedList, less)
+}
+
+func singleOf(key int, item int) *datastruct.SingleLinkedList[int, int] {
+	sll := datastruct.NewSingleLinkedList[int, int]()
+	sll.InsertHead(key, item)
+	return sll
+}
+
+type doubleSortable struct {
+	*datastruct.DoubleLinkedList[int, int]
+}
+
+func (l doubleSortable) InsertTail(key int, item int) {
+	l.DoubleLinkedList.InsertTail(key, item)
+}
+
+func (l doubleSortable) MergeSorted(other doubleSortable, less func(a, b int) bool) {
+	l.DoubleLinkedList.MergeSorted(other.DoubleLinkedList, less)
+}
+
+func reverse(keys []int) []int {
+	var reversed []int
+	for i := len(keys) - 1; i >= 0; i-- {
+		reversed = append(reversed, keys[i])
+	}
+	return reversed
+}
+
+func TestSingleLinkedList_SortProperties(t *testing.T) {
+	checkSortProperties(t, func() singleSortable {
+		return singleSortable{datastruct.NewSingleLinkedList[int, int]()}
+	}, func(t *testing.T, l singleSortable) {})
+}
+
+func TestDoubleLinkedList_SortProperties(t *testing.T) {
+	checkSortProperties(t, func() doubleSortable {
+		return doubleSortable{datastruct.NewDoubleLinkedList[int, int]()}
+	}, func(t *testing.T, l doubleSortable) {
+		// the links back to the previous nodes must have been fixed too
+		assert.Equal(t, reverse(l.AllKeys()), l.AllKeysReverse())
+	})
+}
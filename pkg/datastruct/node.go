@@ -30,6 +30,8 @@ type doublyNode[K comparable, V any] struct {
 	Item     V
 	Next     *doublyNode[K, V]
 	Previous *doublyNode[K, V]
+	// element is the node's handle, if one was handed out
+	element *Element[K, V]
 }
 
 func newDoublyNode[K comparable, V any](key K, item V, prev *doublyNode[K, V], next *doublyNode[K, V]) *doublyNode[K, V] {
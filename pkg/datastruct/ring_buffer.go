@@ -0,0 +1,106 @@
+package datastruct
+
+import (
+	"errors"
+	"iter"
+)
+
+// RingPolicy decides what a full RingBuffer does when another value is pushed.
+type RingPolicy int8
+
+const (
+	// RingOverwrite drops the oldest value to make room.
+	RingOverwrite RingPolicy = iota
+	// RingReject fails the push.
+	RingReject
+)
+
+// RingBuffer is a fixed capacity FIFO queue on an array, which never allocates after it is created.
+type RingBuffer[T any] struct {
+	values []T
+	start  int
+	length int
+	policy RingPolicy
+}
+
+func NewRingBuffer[T any](capacity int, policy RingPolicy) *RingBuffer[T] {
+	return &RingBuffer[T]{
+		values: make([]T, capacity),
+		policy: policy,
+	}
+}
+
+// Push adds v as the newest value. If the buffer is full it either overwrites the oldest value or fails,
+// depending on the policy.
+func (r *RingBuffer[T]) Push(v T) error {
+	if r.Full() {
+		if r.policy == RingReject || len(r.values) == 0 {
+			return errors.New("ring buffer is full")
+		}
+		r.Pop()
+	}
+	r.values[r.index(r.length)] = v
+	r.length++
+	return nil
+}
+
+// Pop removes and returns the oldest value, or false if the buffer is empty.
+func (r *RingBuffer[T]) Pop() (T, bool) {
+	var zero T
+	if r.length == 0 {
+		return zero, false
+	}
+	v := r.values[r.start]
+	// clear the slot so that the buffer does not keep the value alive
+	r.values[r.start] = zero
+	r.start = r.index(1)
+	r.length--
+	return v, true
+}
+
+// Peek returns the oldest value without removing it, or false if the buffer is empty.
+func (r *RingBuffer[T]) Peek() (T, bool) {
+	return r.At(0)
+}
+
+// PeekNewest returns the newest value without removing it, or false if the buffer is empty.
+func (r *RingBuffer[T]) PeekNewest() (T, bool) {
+	return r.At(r.length - 1)
+}
+
+// At returns the value at index i counting from 0 at the oldest, or false if i is out of range.
+func (r *RingBuffer[T]) At(i int) (T, bool) {
+	if i < 0 || i >= r.length {
+		var zero T
+		return zero, false
+	}
+	return r.values[r.index(i)], true
+}
+
+func (r *RingBuffer[T]) Len() int {
+	return r.length
+}
+
+func (r *RingBuffer[T]) Cap() int {
+	return len(r.values)
+}
+
+func (r *RingBuffer[T]) Full() bool {
+	return r.length == len(r.values)
+}
+
+// All returns an iterator over the values from oldest to newest.
+func (r *RingBuffer[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < r.length; i++ {
+			if !yield(r.values[r.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// index returns the position in the array of the i-th value from the oldest.
+func (r *RingBuffer[T]) index(i int) int {
+	return (r.start + i) % len(r.values)
+}
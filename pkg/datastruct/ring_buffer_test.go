@@ -0,0 +1,109 @@
+package datastruct_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingBuffer_Push(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		capacity int
+		policy   datastruct.RingPolicy
+		pushes   []int
+		wantErrs []error
+		want     []int
+	}{
+		{
+			desc:     "within capacity",
+			capacity: 3,
+			policy:   datastruct.RingReject,
+			pushes:   []int{1, 2},
+			wantErrs: []error{nil, nil},
+			want:     []int{1, 2},
+		},
+		{
+			desc:     "reject when full",
+			capacity: 2,
+			policy:   datastruct.RingReject,
+			pushes:   []int{1, 2, 3},
+			wantErrs: []error{nil, nil, errors.New("ring buffer is full")},
+			want:     []int{1, 2},
+		},
+		{
+			desc:     "overwrite the oldest when full",
+			capacity: 2,
+			policy:   datastruct.RingOverwrite,
+			pushes:   []int{1, 2, 3, 4, 5},
+			wantErrs: []error{nil, nil, nil, nil, nil},
+			want:     []int{4, 5},
+		},
+		{
+			desc:     "no capacity",
+			capacity: 0,
+			policy:   datastruct.RingOverwrite,
+			pushes:   []int{1},
+			wantErrs: []error{errors.New("ring buffer is full")},
+			want:     nil,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			r := datastruct.NewRingBuffer[int](tC.capacity, tC.policy)
+
+			for i, v := range tC.pushes {
+				assert.Equal(t, tC.wantErrs[i], r.Push(v))
+			}
+
+			assert.Equal(t, tC.want, slices.Collect(r.All()))
+			assert.Equal(t, len(tC.want), r.Len())
+			assert.Equal(t, tC.capacity, r.Cap())
+		})
+	}
+}
+
+func TestRingBuffer_Pop(t *testing.T) {
+	r := datastruct.NewRingBuffer[int](3, datastruct.RingOverwrite)
+
+	_, ok := r.Pop()
+	assert.False(t, ok)
+	_, ok = r.Peek()
+	assert.False(t, ok)
+	_, ok = r.PeekNewest()
+	assert.False(t, ok)
+
+	// wrap round the end of the array
+	for v := 1; v <= 5; v++ {
+		r.Push(v)
+	}
+	assert.True(t, r.Full())
+
+	oldest, _ := r.Peek()
+	newest, _ := r.PeekNewest()
+	assert.Equal(t, 3, oldest)
+	assert.Equal(t, 5, newest)
+	v, ok := r.At(1)
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+	_, ok = r.At(3)
+	assert.False(t, ok)
+
+	var popped []int
+	for {
+		v, ok := r.Pop()
+		if !ok {
+			break
+		}
+		popped = append(popped, v)
+	}
+	assert.Equal(t, []int{3, 4, 5}, popped)
+	assert.Equal(t, 0, r.Len())
+	assert.False(t, r.Full())
+
+	r.Push(6)
+	assert.Equal(t, []int{6}, slices.Collect(r.All()))
+}
@@ -1,39 +1,166 @@
 package datastruct
 
+import (
+	"errors"
+	"iter"
+)
+
 type SingleLinkedList[K comparable, V any] struct {
-	head *singlyNode[K, V]
+	head   *singlyNode[K, V]
+	tail   *singlyNode[K, V]
+	length int
+	policy DuplicatePolicy
 }
 
 func NewSingleLinkedList[K comparable, V any]() *SingleLinkedList[K, V] {
-	return &SingleLinkedList[K, V]{head: nil}
+	return &SingleLinkedList[K, V]{head: nil, tail: nil}
+}
+
+// WithDuplicatePolicy sets what inserting a key that is already in the list does, which is AllowDuplicates by
+// default. It should be set before anything is inserted.
+func (sll *SingleLinkedList[K, V]) WithDuplicatePolicy(policy DuplicatePolicy) *SingleLinkedList[K, V] {
+	sll.policy = policy
+	return sll
 }
 
+// InsertHead adds the key and item at the head. A key the list rejects is not inserted, TryInsertHead reports it.
 func (sll *SingleLinkedList[K, V]) InsertHead(key K, item V) {
-	if sll.isEmpty() {
-		sll.head = newSinglyNode(key, item, nil)
-	} else {
-		sll.head = sll.head.AddNodeBefore(key, item)
-	}
+	sll.TryInsertHead(key, item)
+}
+
+// TryInsertHead adds the key and item at the head, returning ErrDuplicateKey if the list rejects duplicates and
+// already has the key.
+func (sll *SingleLinkedList[K, V]) TryInsertHead(key K, item V) error {
+	return sll.insert(key, item, func() {
+		if sll.isEmpty() {
+			sll.head = newSinglyNode(key, item, nil)
+			sll.tail = sll.head
+		} else {
+			sll.head = sll.head.AddNodeBefore(key, item)
+		}
+		sll.length++
+	})
 }
 
+// InsertAfter adds the key and item after keyAfter, returning false if keyAfter is not in the list or the list
+// rejects keyInsert.
 func (sll *SingleLinkedList[K, V]) InsertAfter(keyAfter K, keyInsert K, itemInsert V) bool {
+	return sll.TryInsertAfter(keyAfter, keyInsert, itemInsert) == nil
+}
+
+// TryInsertAfter adds the key and item after keyAfter, returning an error if keyAfter is not in the list or the
+// list rejects keyInsert.
+func (sll *SingleLinkedList[K, V]) TryInsertAfter(keyAfter K, keyInsert K, itemInsert V) error {
 	node := sll.findNode(keyAfter)
+	if node == nil {
+		return errors.New("key not found")
+	}
+	return sll.insert(keyInsert, itemInsert, func() {
+		sll.insertAfter(node, keyInsert, itemInsert)
+	})
+}
+
+func (sll *SingleLinkedList[K, V]) Delete(key K) bool {
+	previous, node := sll.findNodeBefore(key)
 	if node != nil {
-		node.AddNodeAfter(keyInsert, itemInsert)
+		sll.remove(previous, node)
 		return true
 	}
 	return false
 }
 
-func (sll *SingleLinkedList[K, V]) Delete(key K) bool {
-	previous := sll.findNodeBefore(key)
+// DeleteAll deletes every node with key, returning how many were deleted.
+func (sll *SingleLinkedList[K, V]) DeleteAll(key K) int {
+	deleted := 0
+	var previous *singlyNode[K, V]
+	current := sll.head
+	for current != nil {
+		next := current.Next
+		if current.Key == key {
+			sll.remove(previous, current)
+			deleted++
+		} else {
+			previous = current
+		}
+		current = next
+	}
+	return deleted
+}
+
+// Len returns the number of nodes in the list.
+func (sll *SingleLinkedList[K, V]) Len() int {
+	return sll.length
+}
+
+// Front returns the key and item at the head without removing them, or false if the list is empty.
+func (sll *SingleLinkedList[K, V]) Front() (K, V, bool) {
+	if sll.isEmpty() {
+		var key K
+		var item V
+		return key, item, false
+	}
+	return sll.head.Key, sll.head.Item, true
+}
+
+// Back returns the key and item at the tail without removing them, or false if the list is empty.
+func (sll *SingleLinkedList[K, V]) Back() (K, V, bool) {
+	if sll.isEmpty() {
+		var key K
+		var item V
+		return key, item, false
+	}
+	return sll.tail.Key, sll.tail.Item, true
+}
+
+// At returns the key and item at index i counting from 0 at the head, or false if i is out of range.
+func (sll *SingleLinkedList[K, V]) At(i int) (K, V, bool) {
+	node := sll.nodeAt(i)
+	if node == nil {
+		var key K
+		var item V
+		return key, item, false
+	}
+	return node.Key, node.Item, true
+}
+
+// InsertAt adds the key and item so that they end up at index i, which can be from 0 up to Len, returning false if
+// i is out of range or the list rejects the key.
+func (sll *SingleLinkedList[K, V]) InsertAt(i int, key K, item V) bool {
+	return sll.TryInsertAt(i, key, item) == nil
+}
+
+// TryInsertAt adds the key and item so that they end up at index i, which can be from 0 up to Len, returning an
+// error if i is out of range or the list rejects the key.
+func (sll *SingleLinkedList[K, V]) TryInsertAt(i int, key K, item V) error {
+	if i < 0 || i > sll.length {
+		return errors.New("index out of range")
+	}
+	if i == 0 {
+		return sll.TryInsertHead(key, item)
+	}
+	return sll.insert(key, item, func() {
+		sll.insertAfter(sll.nodeAt(i-1), key, item)
+	})
+}
+
+// RemoveAt removes the node at index i and returns its key and item, or false if i is out of range.
+func (sll *SingleLinkedList[K, V]) RemoveAt(i int) (K, V, bool) {
+	if i < 0 || i >= sll.length {
+		var key K
+		var item V
+		return key, item, false
+	}
+
+	var previous *singlyNode[K, V]
+	if i > 0 {
+		previous = sll.nodeAt(i - 1)
+	}
+	node := sll.head
 	if previous != nil {
-		nodeToDelete := previous.Next
-		previous.Next = nodeToDelete.Next
-		nodeToDelete.Next = nil
-		return true
+		node = previous.Next
 	}
-	return false
+	sll.remove(previous, node)
+	return node.Key, node.Item, true
 }
 
 func (sll *SingleLinkedList[K, V]) Search(key K) (bool, V) {
@@ -45,6 +172,17 @@ func (sll *SingleLinkedList[K, V]) Search(key K) (bool, V) {
 	return true, node.Item
 }
 
+// SearchAll returns the items of every node with key, from head to tail.
+func (sll *SingleLinkedList[K, V]) SearchAll(key K) []V {
+	var vals []V
+	for current := sll.head; current != nil; current = current.Next {
+		if current.Key == key {
+			vals = append(vals, current.Item)
+		}
+	}
+	return vals
+}
+
 func (sll *SingleLinkedList[K, V]) AllKeys() []K {
 	var keys []K
 	current := sll.head
@@ -65,6 +203,235 @@ func (sll *SingleLinkedList[K, V]) AllItems() []V {
 	return vals
 }
 
+// All returns an iterator over the keys and items from head to tail.
+func (sll *SingleLinkedList[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for current := sll.head; current != nil; current = current.Next {
+			if !yield(current.Key, current.Item) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the keys and items from tail to head. As the nodes only link forward, it
+// collects them first, so it allocates in proportion to the length of the list.
+func (sll *SingleLinkedList[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var nodes []*singlyNode[K, V]
+		for current := sll.head; current != nil; current = current.Next {
+			nodes = append(nodes, current)
+		}
+		for i := len(nodes) - 1; i >= 0; i-- {
+			if !yield(nodes[i].Key, nodes[i].Item) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over the keys from head to tail.
+func (sll *SingleLinkedList[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range sll.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the items from head to tail.
+func (sll *SingleLinkedList[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, item := range sll.All() {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// PushBackList moves the nodes of other to the tail of the list in O(1), leaving other empty.
+func (sll *SingleLinkedList[K, V]) PushBackList(other *SingleLinkedList[K, V]) {
+	if other == sll || other.isEmpty() {
+		return
+	}
+	if sll.isEmpty() {
+		sll.head = other.head
+	} else {
+		sll.tail.Next = other.head
+	}
+	sll.tail = other.tail
+	sll.length += other.length
+	other.head, other.tail, other.length = nil, nil, 0
+}
+
+// PushFrontList moves the nodes of other to the head of the list in O(1), leaving other empty.
+func (sll *SingleLinkedList[K, V]) PushFrontList(other *SingleLinkedList[K, V]) {
+	if other == sll || other.isEmpty() {
+		return
+	}
+	if sll.isEmpty() {
+		sll.tail = other.tail
+	} else {
+		other.tail.Next = sll.head
+	}
+	sll.head = other.head
+	sll.length += other.length
+	other.head, other.tail, other.length = nil, nil, 0
+}
+
+// Reverse reverses the order of the list in place.
+func (sll *SingleLinkedList[K, V]) Reverse() {
+	var previous *singlyNode[K, V]
+	current := sll.head
+	sll.tail = sll.head
+	for current != nil {
+		next := current.Next
+		current.Next = previous
+		previous = current
+		current = next
+	}
+	sll.head = previous
+}
+
+// SplitAt keeps the first i nodes in the list and moves the rest into a new list, which is returned.
+func (sll *SingleLinkedList[K, V]) SplitAt(i int) *SingleLinkedList[K, V] {
+	rest := NewSingleLinkedList[K, V]()
+	if i <= 0 {
+		rest.PushBackList(sll)
+		return rest
+	}
+
+	if i >= sll.length {
+		return rest
+	}
+
+	last := sll.nodeAt(i - 1)
+	rest.head, rest.tail, rest.length = last.Next, sll.tail, sll.length-i
+	last.Next = nil
+	sll.tail, sll.length = last, i
+	return rest
+}
+
+// MergeSorted merges the nodes of other into the list, leaving other empty. Both lists must already be sorted
+// by less on their items, and equal items from the list come before those from other.
+func (sll *SingleLinkedList[K, V]) MergeSorted(other *SingleLinkedList[K, V], less func(a, b V) bool) {
+	if other == sll {
+		return
+	}
+	sll.setNodes(mergeSingly(sll.head, other.head, less))
+	sll.length += other.length
+	other.head, other.tail, other.length = nil, nil, 0
+}
+
+// Sort sorts the list by less on the items with a merge sort on the nodes. It is stable, so equal items keep
+// their order.
+func (sll *SingleLinkedList[K, V]) Sort(less func(a, b V) bool) {
+	sll.setNodes(sortSingly(sll.head, less))
+}
+
+// setNodes makes head the head of the list, walking to its tail.
+func (sll *SingleLinkedList[K, V]) setNodes(head *singlyNode[K, V]) {
+	sll.head = head
+	sll.tail = head
+	for sll.tail != nil && !sll.tail.IsTail() {
+		sll.tail = sll.tail.Next
+	}
+}
+
+// sortSingly splits the nodes from head in half, sorts each half and merges them.
+func sortSingly[K comparable, V any](head *singlyNode[K, V], less func(a, b V) bool) *singlyNode[K, V] {
+	if head == nil || head.IsTail() {
+		return head
+	}
+
+	slow, fast := head, head.Next
+	for fast != nil && fast.Next != nil {
+		slow = slow.Next
+		fast = fast.Next.Next
+	}
+	mid := slow.Next
+	slow.Next = nil
+
+	return mergeSingly(sortSingly(head, less), sortSingly(mid, less), less)
+}
+
+// mergeSingly merges the sorted nodes from a and b, taking from a when items are equal so that the merge is
+// stable.
+func mergeSingly[K comparable, V any](a *singlyNode[K, V], b *singlyNode[K, V], less func(a, b V) bool) *singlyNode[K, V] {
+	var start singlyNode[K, V]
+	last := &start
+	for a != nil && b != nil {
+		if less(b.Item, a.Item) {
+			last.Next, b = b, b.Next
+		} else {
+			last.Next, a = a, a.Next
+		}
+		last = last.Next
+	}
+	if a != nil {
+		last.Next = a
+	} else {
+		last.Next = b
+	}
+	return start.Next
+}
+
+// insert applies the duplicate policy to key, only calling insert to add a new node if the key is not rejected or
+// upserted.
+func (sll *SingleLinkedList[K, V]) insert(key K, item V, insert func()) error {
+	if sll.policy != AllowDuplicates {
+		if node := sll.findNode(key); node != nil {
+			if sll.policy == RejectDuplicates {
+				return ErrDuplicateKey
+			}
+			node.Item = item
+			return nil
+		}
+	}
+	insert()
+	return nil
+}
+
+func (sll *SingleLinkedList[K, V]) insertAfter(node *singlyNode[K, V], key K, item V) {
+	newNode := node.AddNodeAfter(key, item)
+	if newNode.IsTail() {
+		sll.tail = newNode
+	}
+	sll.length++
+}
+
+// remove unlinks node, where previous is the node before it or nil if node is the head.
+func (sll *SingleLinkedList[K, V]) remove(previous *singlyNode[K, V], node *singlyNode[K, V]) {
+	if previous != nil {
+		previous.Next = node.Next
+	} else { // node is head
+		sll.head = node.Next
+	}
+	if node.IsTail() {
+		sll.tail = previous
+	}
+	node.Next = nil
+	sll.length--
+}
+
+// nodeAt returns the node at index i, or nil if i is out of range.
+func (sll *SingleLinkedList[K, V]) nodeAt(i int) *singlyNode[K, V] {
+	if i < 0 || i >= sll.length {
+		return nil
+	}
+	if i == sll.length-1 {
+		return sll.tail
+	}
+	current := sll.head
+	for ; i > 0; i-- {
+		current = current.Next
+	}
+	return current
+}
+
 func (sll *SingleLinkedList[K, V]) isEmpty() bool {
 	return sll.head == nil
 }
@@ -80,15 +447,16 @@ func (sll *SingleLinkedList[K, V]) findNode(key K) *singlyNode[K, V] {
 	return nil
 }
 
-func (sll *SingleLinkedList[K, V]) findNodeBefore(key K) *singlyNode[K, V] {
+// findNodeBefore returns the node with key and the node before it, which is nil if the node is the head.
+func (sll *SingleLinkedList[K, V]) findNodeBefore(key K) (*singlyNode[K, V], *singlyNode[K, V]) {
 	var previous *singlyNode[K, V]
 	current := sll.head
 	for current != nil {
 		if current.Key == key {
-			return previous
+			return previous, current
 		}
 		previous = current
 		current = current.Next
 	}
-	return nil
+	return nil, nil
 }
@@ -1,6 +1,9 @@
 package datastruct_test
 
 import (
+	"errors"
+	"slices"
+	"strconv"
 	"testing"
 
 	"github.com/edfoh/data-structures/pkg/datastruct"
@@ -91,6 +94,14 @@ func TestSingleLinkedList_Delete(t *testing.T) {
 		assert.Equal(t, []int{3, 2}, sll.AllKeys())
 		assert.Equal(t, []string{"3", "2"}, sll.AllItems())
 	})
+
+	t.Run("delete the head works", func(t *testing.T) {
+		success := sll.Delete(3)
+
+		assert.True(t, success)
+		assert.Equal(t, []int{2}, sll.AllKeys())
+		assert.Equal(t, []string{"2"}, sll.AllItems())
+	})
 }
 
 func TestSingleLinkedList_Search(t *testing.T) {
@@ -113,3 +124,276 @@ func TestSingleLinkedList_Search(t *testing.T) {
 		assert.Equal(t, "", val)
 	})
 }
+
+func TestSingleLinkedList_Iterators(t *testing.T) {
+	sll := datastruct.NewSingleLinkedList[int, string]()
+
+	t.Run("empty list yields nothing", func(t *testing.T) {
+		assert.Empty(t, slices.Collect(sll.Keys()))
+		assert.Empty(t, slices.Collect(sll.Values()))
+	})
+
+	sll.InsertHead(3, "3")
+	sll.InsertHead(2, "2")
+	sll.InsertHead(1, "1")
+
+	t.Run("all and backward yield keys and items", func(t *testing.T) {
+		var keys []int
+		var items []string
+		for key, item := range sll.All() {
+			keys = append(keys, key)
+			items = append(items, item)
+		}
+		assert.Equal(t, []int{1, 2, 3}, keys)
+		assert.Equal(t, []string{"1", "2", "3"}, items)
+
+		keys, items = nil, nil
+		for key, item := range sll.Backward() {
+			keys = append(keys, key)
+			items = append(items, item)
+		}
+		assert.Equal(t, []int{3, 2, 1}, keys)
+		assert.Equal(t, []string{"3", "2", "1"}, items)
+	})
+
+	t.Run("keys and values match the slices", func(t *testing.T) {
+		assert.Equal(t, sll.AllKeys(), slices.Collect(sll.Keys()))
+		assert.Equal(t, sll.AllItems(), slices.Collect(sll.Values()))
+	})
+
+	t.Run("stops early", func(t *testing.T) {
+		var keys []int
+		for key := range sll.Keys() {
+			keys = append(keys, key)
+			if key == 2 {
+				break
+			}
+		}
+		assert.Equal(t, []int{1, 2}, keys)
+
+		keys = nil
+		for key := range sll.Backward() {
+			keys = append(keys, key)
+			break
+		}
+		assert.Equal(t, []int{3}, keys)
+	})
+}
+
+func newSingleLinkedListOf(keys ...int) *datastruct.SingleLinkedList[int, string] {
+	sll := datastruct.NewSingleLinkedList[int, string]()
+	for i := len(keys) - 1; i >= 0; i-- {
+		sll.InsertHead(keys[i], strconv.Itoa(keys[i]))
+	}
+	return sll
+}
+
+func TestSingleLinkedList_PushList(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		keys     []int
+		other    []int
+		front    bool
+		wantKeys []int
+	}{
+		{desc: "push back", keys: []int{1, 2}, other: []int{3, 4}, wantKeys: []int{1, 2, 3, 4}},
+		{desc: "push front", keys: []int{1, 2}, other: []int{3, 4}, front: true, wantKeys: []int{3, 4, 1, 2}},
+		{desc: "push back onto empty", other: []int{3, 4}, wantKeys: []int{3, 4}},
+		{desc: "push front onto empty", other: []int{3, 4}, front: true, wantKeys: []int{3, 4}},
+		{desc: "push back empty", keys: []int{1, 2}, wantKeys: []int{1, 2}},
+		{desc: "push front empty", keys: []int{1, 2}, front: true, wantKeys: []int{1, 2}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			sll := newSingleLinkedListOf(tC.keys...)
+			other := newSingleLinkedListOf(tC.other...)
+
+			if tC.front {
+				sll.PushFrontList(other)
+			} else {
+				sll.PushBackList(other)
+			}
+
+			assert.Equal(t, tC.wantKeys, sll.AllKeys())
+			assert.Equal(t, len(tC.wantKeys), sll.Len())
+			assert.Empty(t, other.AllKeys())
+			assert.Equal(t, 0, other.Len())
+
+			// the tail must still be right for the next push
+			sll.PushBackList(newSingleLinkedListOf(9))
+			assert.Equal(t, append(tC.wantKeys, 9), sll.AllKeys())
+		})
+	}
+}
+
+func TestSingleLinkedList_Reverse(t *testing.T) {
+	testCases := []struct {
+		desc string
+		keys []int
+		want []int
+	}{
+		{desc: "empty", keys: nil, want: nil},
+		{desc: "one key", keys: []int{1}, want: []int{1}},
+		{desc: "several keys", keys: []int{1, 2, 3}, want: []int{3, 2, 1}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			sll := newSingleLinkedListOf(tC.keys...)
+
+			sll.Reverse()
+
+			assert.Equal(t, tC.want, sll.AllKeys())
+			sll.PushBackList(newSingleLinkedListOf(9))
+			assert.Equal(t, append(tC.want, 9), sll.AllKeys())
+		})
+	}
+}
+
+func TestSingleLinkedList_SplitAt(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		i        int
+		wantKeys []int
+		wantRest []int
+	}{
+		{desc: "split in the middle", i: 2, wantKeys: []int{1, 2}, wantRest: []int{3, 4}},
+		{desc: "split at 0", i: 0, wantKeys: nil, wantRest: []int{1, 2, 3, 4}},
+		{desc: "split at the length", i: 4, wantKeys: []int{1, 2, 3, 4}, wantRest: nil},
+		{desc: "split past the length", i: 7, wantKeys: []int{1, 2, 3, 4}, wantRest: nil},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			sll := newSingleLinkedListOf(1, 2, 3, 4)
+
+			rest := sll.SplitAt(tC.i)
+
+			assert.Equal(t, tC.wantKeys, sll.AllKeys())
+			assert.Equal(t, tC.wantRest, rest.AllKeys())
+			assert.Equal(t, len(tC.wantKeys), sll.Len())
+			assert.Equal(t, len(tC.wantRest), rest.Len())
+
+			sll.PushBackList(rest)
+			assert.Equal(t, []int{1, 2, 3, 4}, sll.AllKeys())
+		})
+	}
+}
+
+func TestSingleLinkedList_Len(t *testing.T) {
+	sll := datastruct.NewSingleLinkedList[int, string]()
+	assert.Equal(t, 0, sll.Len())
+
+	sll.InsertHead(1, "1")
+	sll.InsertHead(2, "2")
+	sll.InsertAfter(1, 3, "3")
+	assert.Equal(t, 3, sll.Len())
+
+	sll.Delete(2)
+	assert.Equal(t, 2, sll.Len())
+	sll.Delete(7)
+	assert.Equal(t, 2, sll.Len())
+
+	sll.Reverse()
+	sll.Sort(func(a, b string) bool { return a < b })
+	assert.Equal(t, 2, sll.Len())
+}
+
+func TestSingleLinkedList_FrontBack(t *testing.T) {
+	sll := datastruct.NewSingleLinkedList[int, string]()
+
+	_, _, ok := sll.Front()
+	assert.False(t, ok)
+	_, _, ok = sll.Back()
+	assert.False(t, ok)
+
+	sll = newSingleLinkedListOf(1, 2, 3)
+
+	key, item, ok := sll.Front()
+	assert.True(t, ok)
+	assert.Equal(t, 1, key)
+	assert.Equal(t, "1", item)
+
+	key, item, ok = sll.Back()
+	assert.True(t, ok)
+	assert.Equal(t, 3, key)
+	assert.Equal(t, "3", item)
+}
+
+func TestSingleLinkedList_At(t *testing.T) {
+	sll := newSingleLinkedListOf(1, 2, 3, 4, 5)
+
+	for i := 0; i < 5; i++ {
+		key, item, ok := sll.At(i)
+
+		assert.True(t, ok)
+		assert.Equal(t, i+1, key)
+		assert.Equal(t, strconv.Itoa(i+1), item)
+	}
+
+	_, _, ok := sll.At(-1)
+	assert.False(t, ok)
+	_, _, ok = sll.At(5)
+	assert.False(t, ok)
+}
+
+func TestSingleLinkedList_InsertAt(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		i        int
+		wantErr  error
+		wantKeys []int
+	}{
+		{desc: "insert at the head", i: 0, wantKeys: []int{9, 1, 2, 3}},
+		{desc: "insert in the middle", i: 1, wantKeys: []int{1, 9, 2, 3}},
+		{desc: "insert before the tail", i: 2, wantKeys: []int{1, 2, 9, 3}},
+		{desc: "insert at the tail", i: 3, wantKeys: []int{1, 2, 3, 9}},
+		{desc: "insert past the tail", i: 4, wantErr: errors.New("index out of range"), wantKeys: []int{1, 2, 3}},
+		{desc: "insert at a negative index", i: -1, wantErr: errors.New("index out of range"), wantKeys: []int{1, 2, 3}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			sll := newSingleLinkedListOf(1, 2, 3)
+
+			err := sll.TryInsertAt(tC.i, 9, "9")
+
+			assert.Equal(t, tC.wantErr, err)
+			assert.Equal(t, tC.wantKeys, sll.AllKeys())
+			assert.Equal(t, len(tC.wantKeys), sll.Len())
+		})
+	}
+}
+
+func TestSingleLinkedList_RemoveAt(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		i        int
+		wantOK   bool
+		wantKey  int
+		wantKeys []int
+	}{
+		{desc: "remove the head", i: 0, wantOK: true, wantKey: 1, wantKeys: []int{2, 3, 4}},
+		{desc: "remove from the middle", i: 1, wantOK: true, wantKey: 2, wantKeys: []int{1, 3, 4}},
+		{desc: "remove nearer the tail", i: 2, wantOK: true, wantKey: 3, wantKeys: []int{1, 2, 4}},
+		{desc: "remove the tail", i: 3, wantOK: true, wantKey: 4, wantKeys: []int{1, 2, 3}},
+		{desc: "remove past the tail", i: 4, wantOK: false, wantKeys: []int{1, 2, 3, 4}},
+		{desc: "remove at a negative index", i: -1, wantOK: false, wantKeys: []int{1, 2, 3, 4}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			sll := newSingleLinkedListOf(1, 2, 3, 4)
+
+			key, item, ok := sll.RemoveAt(tC.i)
+
+			assert.Equal(t, tC.wantOK, ok)
+			if ok {
+				assert.Equal(t, tC.wantKey, key)
+				assert.Equal(t, strconv.Itoa(tC.wantKey), item)
+			}
+			assert.Equal(t, tC.wantKeys, sll.AllKeys())
+			assert.Equal(t, len(tC.wantKeys), sll.Len())
+
+			// the tail must still be right
+			backKey, _, _ := sll.Back()
+			assert.Equal(t, tC.wantKeys[len(tC.wantKeys)-1], backKey)
+		})
+	}
+}
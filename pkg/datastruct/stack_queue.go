@@ -0,0 +1,57 @@
+package datastruct
+
+// Stack is a last in, first out adapter over a Deque, which only uses its back.
+type Stack[T any] struct {
+	deque Deque[T]
+}
+
+// NewStack returns a stack on d, e.g. NewStack[int](NewArrayDeque[int]()).
+func NewStack[T any](d Deque[T]) *Stack[T] {
+	return &Stack[T]{deque: d}
+}
+
+func (s *Stack[T]) Push(v T) {
+	s.deque.PushBack(v)
+}
+
+// Pop removes and returns the value pushed last, or false if the stack is empty.
+func (s *Stack[T]) Pop() (T, bool) {
+	return s.deque.PopBack()
+}
+
+// Peek returns the value pushed last without removing it, or false if the stack is empty.
+func (s *Stack[T]) Peek() (T, bool) {
+	return s.deque.Back()
+}
+
+func (s *Stack[T]) Len() int {
+	return s.deque.Len()
+}
+
+// Queue is a first in, first out adapter over a Deque, which adds at its back and removes from its front.
+type Queue[T any] struct {
+	deque Deque[T]
+}
+
+// NewQueue returns a queue on d, e.g. NewQueue[int](NewLinkedDeque[int]()).
+func NewQueue[T any](d Deque[T]) *Queue[T] {
+	return &Queue[T]{deque: d}
+}
+
+func (q *Queue[T]) Enqueue(v T) {
+	q.deque.PushBack(v)
+}
+
+// Dequeue removes and returns the value enqueued first, or false if the queue is empty.
+func (q *Queue[T]) Dequeue() (T, bool) {
+	return q.deque.PopFront()
+}
+
+// Peek returns the value enqueued first without removing it, or false if the queue is empty.
+func (q *Queue[T]) Peek() (T, bool) {
+	return q.deque.Front()
+}
+
+func (q *Queue[T]) Len() int {
+	return q.deque.Len()
+}
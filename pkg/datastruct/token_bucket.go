@@ -1,12 +1,24 @@
 package datastruct
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"math"
 	"sync"
 	"time"
 )
 
+var now = time.Now
+
+// SetClock replaces the clock used by token buckets, for simulations that run faster than real time, and returns
+// a function that restores the previous clock. It must not be called while token buckets are in use.
+func SetClock(clock func() time.Time) (restore func()) {
+	prev := now
+	now = clock
+	return func() { now = prev }
+}
+
 type TokenBucket struct {
 	sync.Mutex
 	maxTokens           float64
@@ -20,10 +32,29 @@ func NewTokenBucket(maxTokens float64, refillRatePerSecond float64) *TokenBucket
 		maxTokens:           maxTokens,
 		currentTokens:       maxTokens,
 		refillRatePerSecond: refillRatePerSecond,
-		lastTakenTime:       time.Now(),
+		lastTakenTime:       now(),
 	}
 }
 
+// NewTokenBucketWithState creates a bucket that carries on from the tokens it had at its last refill time,
+// e.g. when the bucket's state is kept outside of the process.
+func NewTokenBucketWithState(maxTokens float64, refillRatePerSecond float64, currentTokens float64, lastRefillTime time.Time) *TokenBucket {
+	return &TokenBucket{
+		maxTokens:           maxTokens,
+		currentTokens:       currentTokens,
+		refillRatePerSecond: refillRatePerSecond,
+		lastTakenTime:       lastRefillTime,
+	}
+}
+
+// State returns the tokens and the last refill time that NewTokenBucketWithState carries on from.
+func (b *TokenBucket) State() (float64, time.Time) {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.currentTokens, b.lastTakenTime
+}
+
 func (b *TokenBucket) TakeN(n int) error {
 	b.Lock()
 	defer b.Unlock()
@@ -37,9 +68,97 @@ func (b *TokenBucket) TakeN(n int) error {
 	return nil
 }
 
+// Refund puts n tokens back into the bucket, e.g. when the request they were taken for was cancelled.
+// The bucket never holds more than its max tokens.
+func (b *TokenBucket) Refund(n int) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.currentTokens = math.Min(b.currentTokens+float64(n), b.maxTokens)
+}
+
+type tokenBucketState struct {
+	Tokens     float64   `json:"tokens"`
+	LastRefill time.Time `json:"lastRefill"`
+}
+
+// tokenBucketVersion is the first byte of the binary encoding, so that the encoding can change later.
+const tokenBucketVersion byte = 1
+
+// MarshalBinary encodes the tokens and the last refill time, but not the max tokens or refill rate which come
+// from the bucket it is restored into. As the last refill time is kept, the tokens refilled while the bucket
+// was not in use are added when it is used again.
+func (b *TokenBucket) MarshalBinary() ([]byte, error) {
+	tokens, lastRefill := b.State()
+
+	data := make([]byte, 17)
+	data[0] = tokenBucketVersion
+	binary.BigEndian.PutUint64(data[1:], math.Float64bits(tokens))
+	binary.BigEndian.PutUint64(data[9:], uint64(lastRefill.UnixNano()))
+	return data, nil
+}
+
+func (b *TokenBucket) UnmarshalBinary(data []byte) error {
+	if len(data) != 17 || data[0] != tokenBucketVersion {
+		return errors.New("invalid token bucket encoding")
+	}
+
+	tokens := math.Float64frombits(binary.BigEndian.Uint64(data[1:]))
+	lastRefill := time.Unix(0, int64(binary.BigEndian.Uint64(data[9:])))
+	b.restore(tokens, lastRefill)
+	return nil
+}
+
+func (b *TokenBucket) MarshalJSON() ([]byte, error) {
+	tokens, lastRefill := b.State()
+	return json.Marshal(tokenBucketState{Tokens: tokens, LastRefill: lastRefill})
+}
+
+func (b *TokenBucket) UnmarshalJSON(data []byte) error {
+	var s tokenBucketState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	b.restore(s.Tokens, s.LastRefill)
+	return nil
+}
+
+func (b *TokenBucket) restore(tokens float64, lastRefill time.Time) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.currentTokens = math.Min(tokens, b.maxTokens)
+	b.lastTakenTime = lastRefill
+}
+
+// Tokens returns the number of tokens that can currently be taken.
+func (b *TokenBucket) Tokens() float64 {
+	b.Lock()
+	defer b.Unlock()
+
+	b.refill()
+	return b.currentTokens
+}
+
+func (b *TokenBucket) MaxTokens() float64 {
+	return b.maxTokens
+}
+
+func (b *TokenBucket) RefillRatePerSecond() float64 {
+	return b.refillRatePerSecond
+}
+
+// refill adds whole tokens for the time elapsed since the last refill. The last refill time only moves forward
+// by the time it took to refill those whole tokens, so partial tokens carry over to the next refill.
 func (b *TokenBucket) refill() {
-	timeElapsed := time.Since(b.lastTakenTime)
+	tNow := now()
+	timeElapsed := tNow.Sub(b.lastTakenTime)
 	refillNumTokens := math.Floor(timeElapsed.Seconds() * b.refillRatePerSecond)
 
 	b.currentTokens = math.Min(b.currentTokens+refillNumTokens, b.maxTokens)
+	if b.currentTokens == b.maxTokens || b.refillRatePerSecond == 0 {
+		b.lastTakenTime = tNow
+	} else {
+		b.lastTakenTime = b.lastTakenTime.Add(time.Duration(refillNumTokens / b.refillRatePerSecond * float64(time.Second)))
+	}
 }
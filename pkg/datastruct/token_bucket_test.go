@@ -62,3 +62,181 @@ func TestTokenBucket_TakeN(t *testing.T) {
 		})
 	}
 }
+
+func TestTokenBucket_Refund(t *testing.T) {
+	bucket := NewTokenBucket(10, 0)
+
+	t.Run("refunded tokens can be taken again", func(t *testing.T) {
+		assert.NoError(t, bucket.TakeN(10))
+		bucket.Refund(4)
+
+		assert.NoError(t, bucket.TakeN(4))
+		assert.Equal(t, errors.New("ran out of tokens"), bucket.TakeN(1))
+	})
+
+	t.Run("refund does not go over max tokens", func(t *testing.T) {
+		bucket.Refund(20)
+
+		assert.NoError(t, bucket.TakeN(10))
+		assert.Equal(t, errors.New("ran out of tokens"), bucket.TakeN(1))
+	})
+}
+
+func TestTokenBucket_TakeN_Refill(t *testing.T) {
+	fakeNow := time.Date(2022, 01, 01, 12, 0, 0, 0, time.UTC)
+	restore := SetClock(func() time.Time { return fakeNow })
+	defer restore()
+
+	bucket := NewTokenBucket(10, 2)
+	assert.NoError(t, bucket.TakeN(10))
+
+	testCases := []struct {
+		desc            string
+		fakeTimeElapsed time.Duration
+		takesN          []int
+		wantErrs        []error
+	}{
+		{
+			desc:            "tokens refilled since the last take can be taken",
+			fakeTimeElapsed: 1 * time.Second,
+			takesN:          []int{2, 1},
+			wantErrs:        []error{nil, errors.New("ran out of tokens")},
+		},
+		{
+			desc:            "the time already refilled is not refilled again",
+			fakeTimeElapsed: 1250 * time.Millisecond,
+			takesN:          []int{1},
+			wantErrs:        []error{errors.New("ran out of tokens")},
+		},
+		{
+			desc:            "partial tokens carry over to the next take",
+			fakeTimeElapsed: 1500 * time.Millisecond,
+			takesN:          []int{1, 1},
+			wantErrs:        []error{nil, errors.New("ran out of tokens")},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			restore := SetClock(func() time.Time { return fakeNow.Add(tC.fakeTimeElapsed) })
+			defer restore()
+
+			for i, takeN := range tC.takesN {
+				assert.Equal(t, tC.wantErrs[i], bucket.TakeN(takeN))
+			}
+		})
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	fakeNow := time.Date(2022, 01, 01, 12, 0, 0, 0, time.UTC)
+
+	restore := SetClock(func() time.Time { return fakeNow })
+	_, gotLastRefillTime := NewTokenBucket(10, 1).State()
+	assert.Equal(t, fakeNow, gotLastRefillTime)
+
+	restore()
+	_, gotLastRefillTime = NewTokenBucket(10, 1).State()
+	assert.WithinDuration(t, time.Now(), gotLastRefillTime, 1*time.Second)
+}
+
+func TestTokenBucket_Tokens(t *testing.T) {
+	fakeNow := time.Date(2022, 01, 01, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fakeNow }
+	defer func() { now = time.Now }()
+
+	bucket := NewTokenBucket(10, 2)
+	assert.NoError(t, bucket.TakeN(10))
+
+	testCases := []struct {
+		desc            string
+		fakeTimeElapsed time.Duration
+		wantTokens      float64
+	}{
+		{
+			desc:            "partial tokens are not available",
+			fakeTimeElapsed: 400 * time.Millisecond,
+			wantTokens:      0,
+		},
+		{
+			desc:            "partial tokens carry over to the next refill",
+			fakeTimeElapsed: 800 * time.Millisecond,
+			wantTokens:      1,
+		},
+		{
+			desc:            "refilling again does not count the same time twice",
+			fakeTimeElapsed: 1200 * time.Millisecond,
+			wantTokens:      2,
+		},
+		{
+			desc:            "refill stops at max tokens",
+			fakeTimeElapsed: 1 * time.Hour,
+			wantTokens:      10,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			now = func() time.Time { return fakeNow.Add(tC.fakeTimeElapsed) }
+
+			assert.Equal(t, tC.wantTokens, bucket.Tokens())
+		})
+	}
+}
+
+func TestNewTokenBucketWithState(t *testing.T) {
+	fakeNow := time.Date(2022, 01, 01, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fakeNow }
+	defer func() { now = time.Now }()
+
+	bucket := NewTokenBucketWithState(10, 2, 3, fakeNow.Add(-1750*time.Millisecond))
+
+	assert.NoError(t, bucket.TakeN(6))
+	gotTokens, gotLastRefillTime := bucket.State()
+	assert.Equal(t, 0.0, gotTokens)
+	assert.Equal(t, fakeNow.Add(-250*time.Millisecond), gotLastRefillTime)
+}
+
+func TestTokenBucket_Marshal(t *testing.T) {
+	fakeNow := time.Date(2022, 01, 01, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fakeNow }
+	defer func() { now = time.Now }()
+
+	testCases := []struct {
+		desc      string
+		marshal   func(b *TokenBucket) ([]byte, error)
+		unmarshal func(b *TokenBucket, data []byte) error
+	}{
+		{
+			desc:      "binary",
+			marshal:   (*TokenBucket).MarshalBinary,
+			unmarshal: (*TokenBucket).UnmarshalBinary,
+		},
+		{
+			desc:      "json",
+			marshal:   (*TokenBucket).MarshalJSON,
+			unmarshal: (*TokenBucket).UnmarshalJSON,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			now = func() time.Time { return fakeNow }
+			bucket := NewTokenBucket(10, 1)
+			assert.NoError(t, bucket.TakeN(8))
+
+			data, err := tC.marshal(bucket)
+			assert.NoError(t, err)
+
+			// restarting 5 seconds later should not start with a full bucket, but with the 5 tokens refilled
+			now = func() time.Time { return fakeNow.Add(5 * time.Second) }
+			restored := NewTokenBucket(10, 1)
+			assert.NoError(t, tC.unmarshal(restored, data))
+
+			assert.Equal(t, 7.0, restored.Tokens())
+		})
+	}
+}
+
+func TestTokenBucket_UnmarshalBinary_Invalid(t *testing.T) {
+	bucket := NewTokenBucket(10, 1)
+
+	assert.Equal(t, errors.New("invalid token bucket encoding"), bucket.UnmarshalBinary([]byte{1, 2, 3}))
+}
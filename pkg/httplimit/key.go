@@ -0,0 +1,38 @@
+package httplimit
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// KeyFunc extracts the key that a request is limited by. Requests that have no key share the empty key.
+type KeyFunc func(r *http.Request) string
+
+// KeyByIP limits by the IP address of the connection. Forwarding headers are ignored as they can be spoofed,
+// use KeyByHeader when the server is behind a trusted proxy.
+func KeyByIP() KeyFunc {
+	return func(r *http.Request) string {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			return r.RemoteAddr
+		}
+		return host
+	}
+}
+
+func KeyByHeader(name string) KeyFunc {
+	return func(r *http.Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// KeyByAPIKey limits by the bearer token in the Authorization header, falling back to the X-API-Key header.
+func KeyByAPIKey() KeyFunc {
+	return func(r *http.Request) string {
+		if token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); token != r.Header.Get("Authorization") {
+			return token
+		}
+		return r.Header.Get("X-API-Key")
+	}
+}
@@ -0,0 +1,70 @@
+package httplimit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/edfoh/data-structures/pkg/httplimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyFuncs(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		keyFunc    httplimit.KeyFunc
+		remoteAddr string
+		headers    map[string]string
+		wantKey    string
+	}{
+		{
+			desc:       "ip ignores the port",
+			keyFunc:    httplimit.KeyByIP(),
+			remoteAddr: "10.0.0.1:5000",
+			wantKey:    "10.0.0.1",
+		},
+		{
+			desc:       "ip ignores forwarding headers",
+			keyFunc:    httplimit.KeyByIP(),
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string]string{"X-Forwarded-For": "192.168.0.1"},
+			wantKey:    "10.0.0.1",
+		},
+		{
+			desc:    "header",
+			keyFunc: httplimit.KeyByHeader("X-Tenant"),
+			headers: map[string]string{"X-Tenant": "acme"},
+			wantKey: "acme",
+		},
+		{
+			desc:    "missing header is the empty key",
+			keyFunc: httplimit.KeyByHeader("X-Tenant"),
+			wantKey: "",
+		},
+		{
+			desc:    "api key from bearer token",
+			keyFunc: httplimit.KeyByAPIKey(),
+			headers: map[string]string{"Authorization": "Bearer secret", "X-API-Key": "other"},
+			wantKey: "secret",
+		},
+		{
+			desc:    "api key from header",
+			keyFunc: httplimit.KeyByAPIKey(),
+			headers: map[string]string{"Authorization": "Basic abc", "X-API-Key": "other"},
+			wantKey: "other",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tC.remoteAddr != "" {
+				req.RemoteAddr = tC.remoteAddr
+			}
+			for k, v := range tC.headers {
+				req.Header.Set(k, v)
+			}
+
+			assert.Equal(t, tC.wantKey, tC.keyFunc(req))
+		})
+	}
+}
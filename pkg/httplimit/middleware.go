@@ -0,0 +1,89 @@
+package httplimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/strategy"
+)
+
+// Limiter takes n from the limit of a key. strategy.KeyedLimiter implements it for a limit per key.
+type Limiter interface {
+	Take(key string, n int) strategy.Result
+}
+
+type globalLimiter struct {
+	sync.Mutex
+	limiter strategy.Limiter
+}
+
+// Global applies a single limit to all keys. Requests handled at the same time take from the limiter one after
+// the other, so it does not have to be safe for concurrent use.
+func Global(limiter strategy.Limiter) Limiter {
+	return &globalLimiter{limiter: limiter}
+}
+
+func (g *globalLimiter) Take(key string, n int) strategy.Result {
+	g.Lock()
+	defer g.Unlock()
+
+	return g.limiter.Take(n)
+}
+
+// Middleware rejects requests over the limit of their key with 429 Too Many Requests. Every limited response
+// has the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers, and rejected responses also have
+// Retry-After.
+type Middleware struct {
+	limiter   Limiter
+	keyFunc   KeyFunc
+	allowList map[string]bool
+}
+
+func NewMiddleware(limiter Limiter, keyFunc KeyFunc) *Middleware {
+	return &Middleware{
+		limiter:   limiter,
+		keyFunc:   keyFunc,
+		allowList: make(map[string]bool),
+	}
+}
+
+// Allow adds keys that bypass the limit altogether.
+func (m *Middleware) Allow(keys ...string) *Middleware {
+	for _, key := range keys {
+		m.allowList[key] = true
+	}
+	return m
+}
+
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := m.keyFunc(r)
+		if m.allowList[key] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		res := m.limiter.Take(key, 1)
+		setHeaders(w.Header(), res)
+		if !res.Allowed {
+			w.Header().Set("Retry-After", seconds(res.RetryAfter))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func setHeaders(h http.Header, res strategy.Result) {
+	h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	h.Set("RateLimit-Reset", seconds(res.ResetAfter))
+}
+
+// seconds formats d as whole seconds, rounded up so that clients do not retry too early.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
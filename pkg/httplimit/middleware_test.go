@@ -0,0 +1,111 @@
+package httplimit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/httplimit"
+	"github.com/edfoh/data-structures/pkg/strategy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	limiter := strategy.NewKeyedLimiter[string](func() strategy.Limiter {
+		g, _ := strategy.NewGCRA(1, 1*time.Hour, 2)
+		return g
+	}, 24*time.Hour)
+	handler := httplimit.NewMiddleware(limiter, httplimit.KeyByHeader("X-Tenant")).
+		Allow("internal").
+		Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+	testCases := []struct {
+		desc           string
+		tenant         string
+		wantStatus     int
+		wantLimit      string
+		wantRemaining  string
+		wantReset      string
+		wantRetryAfter string
+	}{
+		{
+			desc:          "first request is allowed",
+			tenant:        "a",
+			wantStatus:    http.StatusNoContent,
+			wantLimit:     "2",
+			wantRemaining: "1",
+			wantReset:     "3600",
+		},
+		{
+			desc:          "second request is allowed",
+			tenant:        "a",
+			wantStatus:    http.StatusNoContent,
+			wantLimit:     "2",
+			wantRemaining: "0",
+			wantReset:     "7200",
+		},
+		{
+			desc:           "third request is rejected",
+			tenant:         "a",
+			wantStatus:     http.StatusTooManyRequests,
+			wantLimit:      "2",
+			wantRemaining:  "0",
+			wantReset:      "7200",
+			wantRetryAfter: "3600",
+		},
+		{
+			desc:          "other key is allowed",
+			tenant:        "b",
+			wantStatus:    http.StatusNoContent,
+			wantLimit:     "2",
+			wantRemaining: "1",
+			wantReset:     "3600",
+		},
+		{
+			desc:       "allow listed key bypasses the limit",
+			tenant:     "internal",
+			wantStatus: http.StatusNoContent,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-Tenant", tC.tenant)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tC.wantStatus, rec.Code)
+			assert.Equal(t, tC.wantLimit, rec.Header().Get("RateLimit-Limit"))
+			assert.Equal(t, tC.wantRemaining, rec.Header().Get("RateLimit-Remaining"))
+			assert.Equal(t, tC.wantReset, rec.Header().Get("RateLimit-Reset"))
+			assert.Equal(t, tC.wantRetryAfter, rec.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestMiddleware_Global(t *testing.T) {
+	limiter, err := strategy.NewGCRA(1, 1*time.Hour, 1)
+	require.NoError(t, err)
+	handler := httplimit.NewMiddleware(httplimit.Global(limiter), httplimit.KeyByIP()).
+		Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, err = http.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "3600", resp.Header.Get("Retry-After"))
+}
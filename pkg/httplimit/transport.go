@@ -0,0 +1,165 @@
+package httplimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/strategy"
+)
+
+// Transport is an http.RoundTripper that waits for the limiter to allow each request before sending it. When the
+// server responds with 429 Too Many Requests and a Retry-After header, all requests are paused until then.
+type Transport struct {
+	sync.Mutex
+	base        http.RoundTripper
+	limiter     strategy.Limiter
+	pausedUntil time.Time
+	observer    strategy.Observer
+	now         func() time.Time
+}
+
+// NewTransport wraps base, or http.DefaultTransport if base is nil. The limiter is only taken from while the
+// transport is locked, so a client can send requests from several goroutines with a limiter that is not safe for
+// concurrent use.
+func NewTransport(base http.RoundTripper, limiter strategy.Limiter) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{
+		base:    base,
+		limiter: limiter,
+		now:     time.Now,
+	}
+}
+
+// WithObserver calls observer with an EventWait every time a request waits for the limiter or a pause.
+func (t *Transport) WithObserver(observer strategy.Observer) *Transport {
+	t.Lock()
+	defer t.Unlock()
+
+	t.observer = observer
+	return t
+}
+
+// WithClock replaces the clock that Retry-After pauses are measured with, e.g. the same clock as the limiter's
+// in tests. Waiting and context deadlines are still in real time.
+func (t *Transport) WithClock(clock func() time.Time) *Transport {
+	t.Lock()
+	defer t.Unlock()
+
+	t.now = clock
+	return t
+}
+
+// RoundTrip closes the request body if the request is not sent, as the base RoundTripper would.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.wait(req.Context()); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if until, ok := parseRetryAfter(resp.Header.Get("Retry-After"), t.clock()); ok {
+			t.pause(until)
+		}
+	}
+	return resp, nil
+}
+
+// wait blocks until the limiter allows a request. It gives up straight away if the wait would go past the
+// context's deadline.
+func (t *Transport) wait(ctx context.Context) error {
+	for {
+		wait, err := t.take()
+		if err != nil || wait == 0 {
+			return err
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return fmt.Errorf("rate limit wait of %s exceeds the deadline: %w", wait, context.DeadlineExceeded)
+		}
+		t.observe(strategy.Event{Kind: strategy.EventWait, Cost: 1, Wait: wait})
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// take returns 0 if a request was taken from the limiter, otherwise how long to wait before trying again.
+func (t *Transport) take() (time.Duration, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	if paused := t.pausedUntil.Sub(t.now()); paused > 0 {
+		return paused, nil
+	}
+
+	res := t.limiter.Take(1)
+	if !res.Allowed && res.RetryAfter <= 0 {
+		return 0, errors.New("rate limiter rejected the request without a retry time")
+	}
+	return res.RetryAfter, nil
+}
+
+func (t *Transport) clock() time.Time {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.now()
+}
+
+func (t *Transport) observe(e strategy.Event) {
+	t.Lock()
+	observer := t.observer
+	t.Unlock()
+
+	if observer != nil {
+		observer.Observe(e)
+	}
+}
+
+func (t *Transport) pause(until time.Time) {
+	t.Lock()
+	defer t.Unlock()
+
+	if until.After(t.pausedUntil) {
+		t.pausedUntil = until
+	}
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string, t time.Time) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return t.Add(time.Duration(secs) * time.Second), true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date, true
+	}
+	return time.Time{}, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
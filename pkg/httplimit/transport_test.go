@@ -0,0 +1,209 @@
+package httplimit_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+	"github.com/edfoh/data-structures/pkg/httplimit"
+	"github.com/edfoh/data-structures/pkg/strategy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTokenBucketClient(maxTokens float64, refillRatePerSecond float64) *http.Client {
+	limiter := strategy.NewTokenBucketLimiter(datastruct.NewTokenBucket(maxTokens, refillRatePerSecond))
+	return &http.Client{Transport: httplimit.NewTransport(nil, limiter)}
+}
+
+func TestTransport_WaitsForTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := newTokenBucketClient(1, 10)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestTransport_ContextDeadline(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := newTokenBucketClient(1, 0.1)
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.Do(req)
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestTransport_PausesOnRetryAfter(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := newTokenBucketClient(10, 10)
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	start := time.Now()
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+}
+
+func TestTransport_WithObserver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var waits int32
+	limiter := strategy.NewTokenBucketLimiter(datastruct.NewTokenBucket(1, 20))
+	transport := httplimit.NewTransport(nil, limiter).WithObserver(strategy.ObserverFunc(func(e strategy.Event) {
+		if e.Kind == strategy.EventWait && e.Wait > 0 {
+			atomic.AddInt32(&waits, 1)
+		}
+	}))
+	client := &http.Client{Transport: transport}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&waits), int32(1))
+}
+
+// closeRecorder is a request body that records whether it was closed.
+type closeRecorder struct {
+	closed int32
+}
+
+func (b *closeRecorder) Read(p []byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (b *closeRecorder) Close() error {
+	atomic.StoreInt32(&b.closed, 1)
+	return nil
+}
+
+func TestTransport_ClosesBodyWhenNotSent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	limiter := strategy.NewTokenBucketLimiter(datastruct.NewTokenBucket(0, 0.1))
+	transport := httplimit.NewTransport(nil, limiter)
+
+	testCases := []struct {
+		desc   string
+		newCtx func() (context.Context, context.CancelFunc)
+	}{
+		{
+			desc: "wait exceeds the deadline",
+			newCtx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 100*time.Millisecond)
+			},
+		},
+		{
+			desc: "context is cancelled while waiting",
+			newCtx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(50*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ctx, cancel := tC.newCtx()
+			defer cancel()
+			body := &closeRecorder{}
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, body)
+			require.NoError(t, err)
+
+			_, err = transport.RoundTrip(req)
+
+			assert.Error(t, err)
+			assert.Equal(t, int32(1), atomic.LoadInt32(&body.closed))
+		})
+	}
+}
+
+func TestTransport_WithClock(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var elapsed atomic.Int64
+	start := time.Now()
+	limiter := strategy.NewTokenBucketLimiter(datastruct.NewTokenBucket(10, 10))
+	transport := httplimit.NewTransport(nil, limiter).WithClock(func() time.Time {
+		return start.Add(time.Duration(elapsed.Load()))
+	})
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	// the pause is over once the clock has moved past Retry-After, without waiting for it in real time
+	elapsed.Store(int64(61 * time.Second))
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Less(t, time.Since(start), 1*time.Second)
+}
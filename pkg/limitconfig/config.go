@@ -0,0 +1,253 @@
+package limitconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/strategy"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	TokenBucket     = "token_bucket"
+	LeakyBucket     = "leaky_bucket"
+	LeakyBucketChan = "leaky_bucket_chan"
+	SlidingWindow   = "sliding_window"
+	FixedWindow     = "fixed_window"
+	GCRA            = "gcra"
+)
+
+// defaultIdleTimeout is how long a key is kept after its last request when idleTimeout is not set.
+const defaultIdleTimeout = 10 * time.Minute
+
+// Config is a set of named limits, usually loaded from a YAML or JSON file:
+//
+//	limits:
+//	  - name: api
+//	    algorithm: token_bucket
+//	    rate: 10
+//	    interval: 1s
+//	    burst: 20
+//	    key: header:X-Tenant
+//	  - name: logins
+//	    composite: [api, per-day]
+type Config struct {
+	Limits []LimitConfig `json:"limits" yaml:"limits"`
+}
+
+// LimitConfig is either an algorithm or a composite of other limits.
+//
+// Rate is how many are allowed per interval, except for leaky_bucket_chan where it is per second and interval is
+// not used. Burst is the capacity of token_bucket, leaky_bucket, leaky_bucket_chan and gcra, and defaults to the
+// rate. The windows are sized to the interval and allow the rate within it, so they do not use burst.
+//
+// Key is where requests get their key from: global (the default), ip, api_key or header:<name>.
+type LimitConfig struct {
+	Name        string   `json:"name" yaml:"name"`
+	Algorithm   string   `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+	Rate        int      `json:"rate,omitempty" yaml:"rate,omitempty"`
+	Interval    Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+	Burst       int      `json:"burst,omitempty" yaml:"burst,omitempty"`
+	Key         string   `json:"key,omitempty" yaml:"key,omitempty"`
+	Composite   []string `json:"composite,omitempty" yaml:"composite,omitempty"`
+	IdleTimeout Duration `json:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty"`
+}
+
+// Duration is a time.Duration written as a string such as "1m30s".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"1s\": %w", err)
+	}
+	return d.parse(s)
+}
+
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	return d.parse(value.Value)
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return time.Duration(d).String(), nil
+}
+
+func (d *Duration) parse(s string) error {
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Load reads a config file, as JSON if it has a .json extension and as YAML otherwise.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	format := "yaml"
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = "json"
+	}
+	return Parse(data, format)
+}
+
+// Parse decodes and validates a config in the given format, either "json" or "yaml". Unknown fields are errors,
+// so that typos are not silently ignored.
+func Parse(data []byte, format string) (Config, error) {
+	var cfg Config
+	switch format {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&cfg); err != nil {
+			return Config{}, err
+		}
+	case "yaml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&cfg); err != nil {
+			return Config{}, err
+		}
+	default:
+		return Config{}, fmt.Errorf("unknown config format %q", format)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate checks every limit and returns an error for the first one that is invalid, naming the limit and the
+// field that is wrong.
+func (c Config) Validate() error {
+	byName := make(map[string]LimitConfig, len(c.Limits))
+	for i, l := range c.Limits {
+		if l.Name == "" {
+			return fmt.Errorf("limit %d: name is required", i)
+		}
+		if _, ok := byName[l.Name]; ok {
+			return fmt.Errorf("limit %q: name is used more than once", l.Name)
+		}
+		byName[l.Name] = l
+	}
+
+	for _, l := range c.Limits {
+		if err := l.validate(byName); err != nil {
+			return fmt.Errorf("limit %q: %w", l.Name, err)
+		}
+	}
+	return nil
+}
+
+func (l LimitConfig) validate(byName map[string]LimitConfig) error {
+	if err := validateKey(l.Key); err != nil {
+		return err
+	}
+	if l.IdleTimeout < 0 {
+		return errors.New("idleTimeout must not be negative")
+	}
+
+	if len(l.Composite) > 0 {
+		if l.Algorithm != "" {
+			return errors.New("a composite cannot also have an algorithm")
+		}
+		if l.Rate != 0 || l.Interval != 0 || l.Burst != 0 {
+			return errors.New("a composite cannot have a rate, interval or burst, they come from its limits")
+		}
+		for _, name := range l.Composite {
+			child, ok := byName[name]
+			if !ok {
+				return fmt.Errorf("composite refers to unknown limit %q", name)
+			}
+			if child.Algorithm == "" || child.Algorithm == LeakyBucketChan {
+				return fmt.Errorf("composite limit %q must have an algorithm other than %s", name, LeakyBucketChan)
+			}
+		}
+		return nil
+	}
+
+	if l.Rate <= 0 {
+		return fmt.Errorf("rate must be greater than 0, got %d", l.Rate)
+	}
+	if l.Burst < 0 {
+		return fmt.Errorf("burst must not be negative, got %d", l.Burst)
+	}
+
+	switch l.Algorithm {
+	case "":
+		return errors.New("algorithm or composite is required")
+	case TokenBucket, LeakyBucket:
+		return validateInterval(l.Interval)
+	case GCRA:
+		if err := validateInterval(l.Interval); err != nil {
+			return err
+		}
+		_, err := strategy.NewGCRA(l.Rate, time.Duration(l.Interval), l.burst())
+		return err
+	case SlidingWindow, FixedWindow:
+		if l.Burst != 0 {
+			return fmt.Errorf("burst is not used by %s, the rate is allowed per interval", l.Algorithm)
+		}
+		return validateInterval(l.Interval)
+	case LeakyBucketChan:
+		if l.Interval != 0 {
+			return fmt.Errorf("interval is not used by %s, the rate is per second", l.Algorithm)
+		}
+		if l.Key != "" && l.Key != "global" {
+			return fmt.Errorf("%s is a single queue and cannot have a key", l.Algorithm)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown algorithm %q", l.Algorithm)
+	}
+}
+
+func validateInterval(interval Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0, got %s", time.Duration(interval))
+	}
+	return nil
+}
+
+func validateKey(key string) error {
+	switch {
+	case key == "", key == "global", key == "ip", key == "api_key":
+		return nil
+	case strings.HasPrefix(key, "header:"):
+		if strings.TrimPrefix(key, "header:") == "" {
+			return errors.New("key header:<name> needs a header name")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown key %q, must be global, ip, api_key or header:<name>", key)
+	}
+}
+
+func (l LimitConfig) burst() int {
+	if l.Burst == 0 {
+		return l.Rate
+	}
+	return l.Burst
+}
+
+func (l LimitConfig) idleTimeout() time.Duration {
+	if l.IdleTimeout == 0 {
+		return defaultIdleTimeout
+	}
+	return time.Duration(l.IdleTimeout)
+}
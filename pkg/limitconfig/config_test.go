@@ -0,0 +1,200 @@
+package limitconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/limitconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	want := limitconfig.Config{
+		Limits: []limitconfig.LimitConfig{
+			{
+				Name:      "api",
+				Algorithm: limitconfig.TokenBucket,
+				Rate:      10,
+				Interval:  limitconfig.Duration(1 * time.Second),
+				Burst:     20,
+				Key:       "header:X-Tenant",
+			},
+			{
+				Name:      "per-day",
+				Algorithm: limitconfig.SlidingWindow,
+				Rate:      1000,
+				Interval:  limitconfig.Duration(24 * time.Hour),
+			},
+			{
+				Name:      "combined",
+				Key:       "ip",
+				Composite: []string{"api", "per-day"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc   string
+		data   string
+		format string
+	}{
+		{
+			desc:   "yaml",
+			format: "yaml",
+			data: `
+limits:
+  - name: api
+    algorithm: token_bucket
+    rate: 10
+    interval: 1s
+    burst: 20
+    key: header:X-Tenant
+  - name: per-day
+    algorithm: sliding_window
+    rate: 1000
+    interval: 24h
+  - name: combined
+    key: ip
+    composite: [api, per-day]
+`,
+		},
+		{
+			desc:   "json",
+			format: "json",
+			data: `{"limits": [
+	{"name": "api", "algorithm": "token_bucket", "rate": 10, "interval": "1s", "burst": 20, "key": "header:X-Tenant"},
+	{"name": "per-day", "algorithm": "sliding_window", "rate": 1000, "interval": "24h"},
+	{"name": "combined", "key": "ip", "composite": ["api", "per-day"]}
+]}`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := limitconfig.Parse([]byte(tC.data), tC.format)
+
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestParse_UnknownField(t *testing.T) {
+	_, err := limitconfig.Parse([]byte(`{"limits": [{"name": "api", "rat": 10}]}`), "json")
+
+	assert.EqualError(t, err, `json: unknown field "rat"`)
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"limits": [{"name": "jobs", "algorithm": "leaky_bucket_chan", "rate": 5}]}`), 0o600))
+
+	got, err := limitconfig.Load(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, []limitconfig.LimitConfig{{Name: "jobs", Algorithm: limitconfig.LeakyBucketChan, Rate: 5}}, got.Limits)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	second := limitconfig.Duration(1 * time.Second)
+
+	testCases := []struct {
+		desc    string
+		limits  []limitconfig.LimitConfig
+		wantErr string
+	}{
+		{
+			desc:    "name is required",
+			limits:  []limitconfig.LimitConfig{{Algorithm: limitconfig.GCRA, Rate: 1, Interval: second}},
+			wantErr: "limit 0: name is required",
+		},
+		{
+			desc: "names are unique",
+			limits: []limitconfig.LimitConfig{
+				{Name: "a", Algorithm: limitconfig.GCRA, Rate: 1, Interval: second},
+				{Name: "a", Algorithm: limitconfig.GCRA, Rate: 1, Interval: second},
+			},
+			wantErr: `limit "a": name is used more than once`,
+		},
+		{
+			desc:    "zero rate in a leaky bucket queue",
+			limits:  []limitconfig.LimitConfig{{Name: "jobs", Algorithm: limitconfig.LeakyBucketChan, Burst: 10}},
+			wantErr: `limit "jobs": rate must be greater than 0, got 0`,
+		},
+		{
+			desc:    "a leaky bucket queue has no interval",
+			limits:  []limitconfig.LimitConfig{{Name: "jobs", Algorithm: limitconfig.LeakyBucketChan, Rate: 1, Interval: second}},
+			wantErr: `limit "jobs": interval is not used by leaky_bucket_chan, the rate is per second`,
+		},
+		{
+			desc:    "a leaky bucket queue has no key",
+			limits:  []limitconfig.LimitConfig{{Name: "jobs", Algorithm: limitconfig.LeakyBucketChan, Rate: 1, Key: "ip"}},
+			wantErr: `limit "jobs": leaky_bucket_chan is a single queue and cannot have a key`,
+		},
+		{
+			desc:    "interval is required",
+			limits:  []limitconfig.LimitConfig{{Name: "a", Algorithm: limitconfig.LeakyBucket, Rate: 1}},
+			wantErr: `limit "a": interval must be greater than 0, got 0s`,
+		},
+		{
+			desc:    "gcra rate is at most 1 per nanosecond of the interval",
+			limits:  []limitconfig.LimitConfig{{Name: "a", Algorithm: limitconfig.GCRA, Rate: 11, Interval: limitconfig.Duration(10)}},
+			wantErr: `limit "a": rate must not be more than 1 per nanosecond of the interval`,
+		},
+		{
+			desc:    "windows have no burst",
+			limits:  []limitconfig.LimitConfig{{Name: "a", Algorithm: limitconfig.FixedWindow, Rate: 1, Interval: second, Burst: 2}},
+			wantErr: `limit "a": burst is not used by fixed_window, the rate is allowed per interval`,
+		},
+		{
+			desc:    "unknown algorithm",
+			limits:  []limitconfig.LimitConfig{{Name: "a", Algorithm: "bucket", Rate: 1, Interval: second}},
+			wantErr: `limit "a": unknown algorithm "bucket"`,
+		},
+		{
+			desc:    "algorithm or composite is required",
+			limits:  []limitconfig.LimitConfig{{Name: "a", Rate: 1, Interval: second}},
+			wantErr: `limit "a": algorithm or composite is required`,
+		},
+		{
+			desc:    "unknown key",
+			limits:  []limitconfig.LimitConfig{{Name: "a", Algorithm: limitconfig.GCRA, Rate: 1, Interval: second, Key: "cookie"}},
+			wantErr: `limit "a": unknown key "cookie", must be global, ip, api_key or header:<name>`,
+		},
+		{
+			desc:    "header key needs a name",
+			limits:  []limitconfig.LimitConfig{{Name: "a", Algorithm: limitconfig.GCRA, Rate: 1, Interval: second, Key: "header:"}},
+			wantErr: `limit "a": key header:<name> needs a header name`,
+		},
+		{
+			desc:    "composite refers to known limits",
+			limits:  []limitconfig.LimitConfig{{Name: "a", Composite: []string{"b"}}},
+			wantErr: `limit "a": composite refers to unknown limit "b"`,
+		},
+		{
+			desc: "composites cannot be nested",
+			limits: []limitconfig.LimitConfig{
+				{Name: "a", Composite: []string{"b"}},
+				{Name: "b", Composite: []string{"a"}},
+			},
+			wantErr: `limit "a": composite limit "b" must have an algorithm other than leaky_bucket_chan`,
+		},
+		{
+			desc: "composites take their rate from their limits",
+			limits: []limitconfig.LimitConfig{
+				{Name: "a", Algorithm: limitconfig.GCRA, Rate: 1, Interval: second},
+				{Name: "b", Composite: []string{"a"}, Rate: 1},
+			},
+			wantErr: `limit "b": a composite cannot have a rate, interval or burst, they come from its limits`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := limitconfig.Config{Limits: tC.limits}.Validate()
+
+			assert.EqualError(t, err, tC.wantErr)
+		})
+	}
+}
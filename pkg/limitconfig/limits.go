@@ -0,0 +1,190 @@
+package limitconfig
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+	"github.com/edfoh/data-structures/pkg/httplimit"
+	"github.com/edfoh/data-structures/pkg/strategy"
+)
+
+// Limit is a limit built from its config, with a separate limiter per key.
+type Limit struct {
+	config     LimitConfig
+	newLimiter func() strategy.Limiter
+	keyed      *strategy.KeyedLimiter[string]
+	keyFunc    httplimit.KeyFunc
+}
+
+func (l *Limit) Name() string {
+	return l.config.Name
+}
+
+func (l *Limit) Config() LimitConfig {
+	return l.config
+}
+
+// NewLimiter returns a new limiter for the limit, not shared with any key.
+func (l *Limit) NewLimiter() strategy.Limiter {
+	return l.newLimiter()
+}
+
+func (l *Limit) KeyFunc() httplimit.KeyFunc {
+	return l.keyFunc
+}
+
+func (l *Limit) Take(key string, n int) strategy.Result {
+	return l.keyed.Take(key, n)
+}
+
+func (l *Limit) Middleware() *httplimit.Middleware {
+	return httplimit.NewMiddleware(l, l.keyFunc)
+}
+
+// Limits are the limits built from a Config. leaky_bucket_chan limits are queues rather than limiters, and are
+// built with NewQueue instead.
+type Limits struct {
+	cfg    Config
+	limits map[string]*Limit
+}
+
+// New validates cfg and builds its limits.
+func New(cfg Config) (*Limits, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]LimitConfig, len(cfg.Limits))
+	for _, l := range cfg.Limits {
+		byName[l.Name] = l
+	}
+
+	limits := &Limits{
+		cfg:    cfg,
+		limits: make(map[string]*Limit),
+	}
+	for _, l := range cfg.Limits {
+		if l.Algorithm == LeakyBucketChan {
+			continue
+		}
+		newLimiter := limiterFactory(l, byName)
+		limits.limits[l.Name] = &Limit{
+			config:     l,
+			newLimiter: newLimiter,
+			keyed:      strategy.NewKeyedLimiter[string](newLimiter, l.idleTimeout()),
+			keyFunc:    keyFunc(l.Key),
+		}
+	}
+	return limits, nil
+}
+
+// LoadFile loads a config file and builds its limits.
+func LoadFile(path string) (*Limits, error) {
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(cfg)
+}
+
+func (ls *Limits) Config() Config {
+	return ls.cfg
+}
+
+func (ls *Limits) Get(name string) (*Limit, bool) {
+	l, ok := ls.limits[name]
+	return l, ok
+}
+
+// NewQueue builds the leaky_bucket_chan limit called name, leaking into out.
+func NewQueue[T any](ls *Limits, name string, out chan<- T) (*strategy.LeakyBucketChan[T], error) {
+	for _, l := range ls.cfg.Limits {
+		if l.Name != name {
+			continue
+		}
+		if l.Algorithm != LeakyBucketChan {
+			return nil, fmt.Errorf("limit %q is not a %s", name, LeakyBucketChan)
+		}
+		return strategy.TryNewLeakyBucketChan(out, l.burst(), l.Rate)
+	}
+	return nil, fmt.Errorf("unknown limit %q", name)
+}
+
+// keep replaces the limits that have the same config as in old with the ones from old, so that their keys keep
+// their state.
+func (ls *Limits) keep(old *Limits) {
+	for name, l := range ls.limits {
+		oldLimit, ok := old.limits[name]
+		if ok && reflect.DeepEqual(ls.configs(l), old.configs(oldLimit)) {
+			ls.limits[name] = oldLimit
+		}
+	}
+}
+
+// configs returns the config of l along with the configs of its composite limits, which it is built from.
+func (ls *Limits) configs(l *Limit) []LimitConfig {
+	configs := []LimitConfig{l.config}
+	for _, name := range l.config.Composite {
+		configs = append(configs, ls.limits[name].config)
+	}
+	return configs
+}
+
+func limiterFactory(l LimitConfig, byName map[string]LimitConfig) func() strategy.Limiter {
+	interval := time.Duration(l.Interval)
+
+	switch l.Algorithm {
+	case TokenBucket:
+		refillRatePerSecond := float64(l.Rate) / interval.Seconds()
+		return func() strategy.Limiter {
+			return strategy.NewTokenBucketLimiter(datastruct.NewTokenBucket(float64(l.burst()), refillRatePerSecond))
+		}
+	case LeakyBucket:
+		return func() strategy.Limiter {
+			return strategy.NewLeakyBucket(l.Rate, interval, l.burst())
+		}
+	case SlidingWindow:
+		return func() strategy.Limiter {
+			return strategy.NewSyncSlidingWindow(interval, l.Rate)
+		}
+	case FixedWindow:
+		return func() strategy.Limiter {
+			return strategy.NewFixedWindow(interval, 0, l.Rate)
+		}
+	case GCRA:
+		return func() strategy.Limiter {
+			// the parameters are checked by Validate, so this cannot fail
+			g, _ := strategy.NewGCRA(l.Rate, interval, l.burst())
+			return g
+		}
+	default:
+		children := make([]func() strategy.Limiter, len(l.Composite))
+		for i, name := range l.Composite {
+			children[i] = limiterFactory(byName[name], byName)
+		}
+		return func() strategy.Limiter {
+			c := strategy.NewCompositeLimiter()
+			for i, name := range l.Composite {
+				c.With(name, children[i]())
+			}
+			return c
+		}
+	}
+}
+
+func keyFunc(key string) httplimit.KeyFunc {
+	switch {
+	case key == "ip":
+		return httplimit.KeyByIP()
+	case key == "api_key":
+		return httplimit.KeyByAPIKey()
+	case strings.HasPrefix(key, "header:"):
+		return httplimit.KeyByHeader(strings.TrimPrefix(key, "header:"))
+	default:
+		return func(r *http.Request) string { return "" }
+	}
+}
@@ -0,0 +1,171 @@
+package limitconfig_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/limitconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLimits(t *testing.T, limits ...limitconfig.LimitConfig) *limitconfig.Limits {
+	t.Helper()
+
+	ls, err := limitconfig.New(limitconfig.Config{Limits: limits})
+	require.NoError(t, err)
+	return ls
+}
+
+func TestNew(t *testing.T) {
+	minute := limitconfig.Duration(1 * time.Minute)
+	testCases := []struct {
+		desc        string
+		limit       limitconfig.LimitConfig
+		wantAllowed int
+	}{
+		{
+			desc:        "token bucket allows the burst",
+			limit:       limitconfig.LimitConfig{Name: "a", Algorithm: limitconfig.TokenBucket, Rate: 2, Interval: minute, Burst: 5},
+			wantAllowed: 5,
+		},
+		{
+			desc:        "burst defaults to the rate",
+			limit:       limitconfig.LimitConfig{Name: "a", Algorithm: limitconfig.GCRA, Rate: 3, Interval: minute},
+			wantAllowed: 3,
+		},
+		{
+			desc:        "leaky bucket allows its capacity",
+			limit:       limitconfig.LimitConfig{Name: "a", Algorithm: limitconfig.LeakyBucket, Rate: 1, Interval: minute, Burst: 4},
+			wantAllowed: 4,
+		},
+		{
+			desc:        "fixed window allows the rate",
+			limit:       limitconfig.LimitConfig{Name: "a", Algorithm: limitconfig.FixedWindow, Rate: 6, Interval: minute},
+			wantAllowed: 6,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			limit, ok := newLimits(t, tC.limit).Get("a")
+			require.True(t, ok)
+
+			allowed := 0
+			for i := 0; i < 10; i++ {
+				if limit.Take("key", 1).Allowed {
+					allowed++
+				}
+			}
+
+			assert.Equal(t, tC.wantAllowed, allowed)
+		})
+	}
+}
+
+func TestNew_RetryAfterPerInterval(t *testing.T) {
+	minute := limitconfig.Duration(1 * time.Minute)
+	testCases := []struct {
+		desc           string
+		limit          limitconfig.LimitConfig
+		wantRetryAfter time.Duration
+	}{
+		{
+			desc:           "leaky bucket leaks the rate per interval",
+			limit:          limitconfig.LimitConfig{Name: "a", Algorithm: limitconfig.LeakyBucket, Rate: 10, Interval: minute},
+			wantRetryAfter: 6 * time.Second,
+		},
+		{
+			desc:           "token bucket refills the rate per interval",
+			limit:          limitconfig.LimitConfig{Name: "a", Algorithm: limitconfig.TokenBucket, Rate: 10, Interval: minute},
+			wantRetryAfter: 6 * time.Second,
+		},
+		{
+			desc:           "gcra emits the rate per interval",
+			limit:          limitconfig.LimitConfig{Name: "a", Algorithm: limitconfig.GCRA, Rate: 10, Interval: minute},
+			wantRetryAfter: 6 * time.Second,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			limit, ok := newLimits(t, tC.limit).Get("a")
+			require.True(t, ok)
+			require.True(t, limit.Take("key", 10).Allowed)
+
+			res := limit.Take("key", 1)
+
+			assert.False(t, res.Allowed)
+			assert.InDelta(t, tC.wantRetryAfter, res.RetryAfter, float64(100*time.Millisecond))
+		})
+	}
+}
+
+func TestNew_Composite(t *testing.T) {
+	ls := newLimits(t,
+		limitconfig.LimitConfig{Name: "burst", Algorithm: limitconfig.TokenBucket, Rate: 1, Interval: limitconfig.Duration(time.Minute), Burst: 3},
+		limitconfig.LimitConfig{Name: "per-hour", Algorithm: limitconfig.FixedWindow, Rate: 2, Interval: limitconfig.Duration(time.Hour)},
+		limitconfig.LimitConfig{Name: "api", Key: "header:X-Tenant", Composite: []string{"burst", "per-hour"}},
+	)
+	limit, ok := ls.Get("api")
+	require.True(t, ok)
+
+	assert.True(t, limit.Take("t1", 1).Allowed)
+	assert.True(t, limit.Take("t1", 1).Allowed)
+	assert.False(t, limit.Take("t1", 1).Allowed)
+	assert.True(t, limit.Take("t2", 1).Allowed)
+
+	// the composite's keys are separate from the keys of the limits it is made of
+	burst, _ := ls.Get("burst")
+	assert.True(t, burst.Take("t1", 3).Allowed)
+}
+
+func TestLimit_Middleware(t *testing.T) {
+	limit, _ := newLimits(t, limitconfig.LimitConfig{
+		Name:      "api",
+		Algorithm: limitconfig.FixedWindow,
+		Rate:      1,
+		Interval:  limitconfig.Duration(time.Hour),
+		Key:       "header:X-Tenant",
+	}).Get("api")
+	handler := limit.Middleware().Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	codes := make([]int, 0, 3)
+	for _, tenant := range []string{"a", "a", "b"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Tenant", tenant)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		codes = append(codes, rec.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK}, codes)
+}
+
+func TestNewQueue(t *testing.T) {
+	ls := newLimits(t,
+		limitconfig.LimitConfig{Name: "jobs", Algorithm: limitconfig.LeakyBucketChan, Rate: 5, Burst: 2},
+		limitconfig.LimitConfig{Name: "api", Algorithm: limitconfig.GCRA, Rate: 1, Interval: limitconfig.Duration(time.Second)},
+	)
+
+	t.Run("builds the queue", func(t *testing.T) {
+		queue, err := limitconfig.NewQueue[int](ls, "jobs", make(chan int))
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, queue.Cap())
+		_, ok := ls.Get("jobs")
+		assert.False(t, ok)
+	})
+
+	t.Run("other algorithms are not queues", func(t *testing.T) {
+		_, err := limitconfig.NewQueue[int](ls, "api", make(chan int))
+
+		assert.EqualError(t, err, `limit "api" is not a leaky_bucket_chan`)
+	})
+
+	t.Run("unknown limit", func(t *testing.T) {
+		_, err := limitconfig.NewQueue[int](ls, "missing", make(chan int))
+
+		assert.EqualError(t, err, `unknown limit "missing"`)
+	})
+}
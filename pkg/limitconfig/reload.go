@@ -0,0 +1,98 @@
+package limitconfig
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reloader keeps the limits loaded from a file, and replaces them when the file is reloaded. Limits whose config
+// did not change keep the state of their keys across reloads.
+type Reloader struct {
+	sync.RWMutex
+	path    string
+	limits  *Limits
+	modTime time.Time
+}
+
+// NewReloader loads the file at path, failing if it is not a valid config.
+func NewReloader(path string) (*Reloader, error) {
+	r := &Reloader{path: path}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reloader) Limits() *Limits {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.limits
+}
+
+// Reload loads the file again. If it is not a valid config, the error is returned and the current limits are kept.
+func (r *Reloader) Reload() error {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return err
+	}
+	limits, err := LoadFile(r.path)
+	if err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if r.limits != nil {
+		limits.keep(r.limits)
+	}
+	r.limits = limits
+	r.modTime = info.ModTime()
+	return nil
+}
+
+// Watch checks the file every interval and reloads it when it has been modified, until ctx is done. Errors from
+// reloading are passed to onError, which can be nil.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration, onError func(err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(r.path)
+		if err == nil {
+			r.RLock()
+			modified := !info.ModTime().Equal(r.modTime)
+			r.RUnlock()
+			if !modified {
+				continue
+			}
+			err = r.Reload()
+		}
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// Handler limits requests to next with the current version of the limit called name.
+func (r *Reloader) Handler(name string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		limit, ok := r.Limits().Get(name)
+		if !ok {
+			http.Error(w, fmt.Sprintf("rate limit %q is not configured", name), http.StatusInternalServerError)
+			return
+		}
+		limit.Middleware().Handler(next).ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,110 @@
+package limitconfig_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/limitconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const reloadConfig = `
+limits:
+  - name: api
+    algorithm: fixed_window
+    rate: %d
+    interval: 1h
+  - name: uploads
+    algorithm: fixed_window
+    rate: 1
+    interval: 1h
+`
+
+func writeConfig(t *testing.T, path string, data string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+}
+
+func TestReloader_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.yaml")
+	writeConfig(t, path, fmt.Sprintf(reloadConfig, 1))
+	r, err := limitconfig.NewReloader(path)
+	require.NoError(t, err)
+
+	api, _ := r.Limits().Get("api")
+	uploads, _ := r.Limits().Get("uploads")
+	require.True(t, api.Take("k", 1).Allowed)
+	require.True(t, uploads.Take("k", 1).Allowed)
+
+	t.Run("changed limits start over and unchanged limits keep their state", func(t *testing.T) {
+		writeConfig(t, path, fmt.Sprintf(reloadConfig, 2))
+		require.NoError(t, r.Reload())
+
+		api, _ := r.Limits().Get("api")
+		uploads, _ := r.Limits().Get("uploads")
+		assert.Equal(t, 2, api.Take("k", 0).Remaining)
+		assert.False(t, uploads.Take("k", 1).Allowed)
+	})
+
+	t.Run("an invalid config keeps the current limits", func(t *testing.T) {
+		writeConfig(t, path, fmt.Sprintf(reloadConfig, 0))
+
+		assert.EqualError(t, r.Reload(), `limit "api": rate must be greater than 0, got 0`)
+		api, _ := r.Limits().Get("api")
+		assert.Equal(t, 2, api.Config().Rate)
+	})
+}
+
+func TestReloader_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.yaml")
+	writeConfig(t, path, fmt.Sprintf(reloadConfig, 1))
+	r, err := limitconfig.NewReloader(path)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Watch(ctx, 10*time.Millisecond, nil)
+
+	writeConfig(t, path, fmt.Sprintf(reloadConfig, 5))
+	// make sure the modification time changes on file systems with a coarse resolution
+	later := time.Now().Add(1 * time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+
+	assert.Eventually(t, func() bool {
+		api, _ := r.Limits().Get("api")
+		return api.Config().Rate == 5
+	}, 1*time.Second, 10*time.Millisecond)
+}
+
+func TestReloader_Handler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.yaml")
+	writeConfig(t, path, fmt.Sprintf(reloadConfig, 1))
+	r, err := limitconfig.NewReloader(path)
+	require.NoError(t, err)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	testCases := []struct {
+		desc     string
+		name     string
+		wantCode int
+	}{
+		{desc: "first request is allowed", name: "api", wantCode: http.StatusOK},
+		{desc: "second request is limited", name: "api", wantCode: http.StatusTooManyRequests},
+		{desc: "unknown limit is an error", name: "missing", wantCode: http.StatusInternalServerError},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.Handler(tC.name, next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tC.wantCode, rec.Code)
+		})
+	}
+}
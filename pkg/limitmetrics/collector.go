@@ -0,0 +1,199 @@
+package limitmetrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+	"github.com/edfoh/data-structures/pkg/strategy"
+)
+
+type metric struct {
+	name string
+	help string
+	kind string
+}
+
+// metrics lists every metric in the order they are rendered.
+var metrics = []metric{
+	{"ratelimit_events_total", "Events by limiter and kind of event.", "counter"},
+	{"ratelimit_cost_total", "Cost of the allowed and denied takes by limiter.", "counter"},
+	{"ratelimit_wait_seconds_total", "Time spent waiting for a limiter.", "counter"},
+	{"ratelimit_remaining", "Remaining capacity after the last take.", "gauge"},
+	{"ratelimit_capacity", "Capacity of the limiter.", "gauge"},
+	{"ratelimit_tokens", "Tokens in a token bucket.", "gauge"},
+	{"ratelimit_bucket_level", "Amount in a leaky bucket that has not leaked out yet.", "gauge"},
+	{"ratelimit_queue_depth", "Tasks waiting in a leaky bucket queue.", "gauge"},
+	{"ratelimit_window_count", "Count of the previous and current sliding windows.", "gauge"},
+}
+
+type sample struct {
+	metric string
+	labels string
+}
+
+// Queue is the part of strategy.LeakyBucketChan the collector reads.
+type Queue interface {
+	Len() int
+	Cap() int
+}
+
+// Collector renders limiter metrics in the Prometheus text exposition format. Events are counted from the
+// observers it returns, and the limiters registered with it are read every time the metrics are rendered. Keys
+// are not used as labels, as there can be too many of them.
+type Collector struct {
+	sync.Mutex
+	values map[sample]float64
+	gauges []func(values map[sample]float64)
+}
+
+func NewCollector() *Collector {
+	return &Collector{
+		values: make(map[sample]float64),
+	}
+}
+
+// Observer returns an observer that counts events under the given limiter name.
+func (c *Collector) Observer(limiter string) strategy.Observer {
+	return strategy.ObserverFunc(func(e strategy.Event) {
+		c.Lock()
+		defer c.Unlock()
+
+		c.values[newSample("ratelimit_events_total", "limiter", limiter, "event", e.Kind.String())]++
+		switch e.Kind {
+		case strategy.EventAllow, strategy.EventDeny:
+			c.values[newSample("ratelimit_cost_total", "limiter", limiter, "event", e.Kind.String())] += float64(e.Cost)
+			c.values[newSample("ratelimit_remaining", "limiter", limiter)] = float64(e.Remaining)
+		case strategy.EventWait:
+			c.values[newSample("ratelimit_wait_seconds_total", "limiter", limiter)] += e.Wait.Seconds()
+		}
+	})
+}
+
+func (c *Collector) TokenBucket(limiter string, bucket *datastruct.TokenBucket) *Collector {
+	return c.gauge(nil, func(values map[sample]float64) {
+		values[newSample("ratelimit_tokens", "limiter", limiter)] = bucket.Tokens()
+		values[newSample("ratelimit_capacity", "limiter", limiter)] = bucket.MaxTokens()
+	})
+}
+
+// LeakyBucket reads the bucket while holding mu, as LeakyBucket is not safe for concurrent use. mu can be nil if
+// the bucket is not used while the metrics are rendered.
+func (c *Collector) LeakyBucket(limiter string, bucket *strategy.LeakyBucket, mu sync.Locker) *Collector {
+	return c.gauge(mu, func(values map[sample]float64) {
+		values[newSample("ratelimit_bucket_level", "limiter", limiter)] = float64(bucket.Count())
+		values[newSample("ratelimit_capacity", "limiter", limiter)] = float64(bucket.Peek(0).Limit)
+	})
+}
+
+func (c *Collector) LeakyBucketChan(limiter string, queue Queue) *Collector {
+	return c.gauge(nil, func(values map[sample]float64) {
+		values[newSample("ratelimit_queue_depth", "limiter", limiter)] = float64(queue.Len())
+		values[newSample("ratelimit_capacity", "limiter", limiter)] = float64(queue.Cap())
+	})
+}
+
+func (c *Collector) SlidingWindow(limiter string, w *strategy.SlidingWindow) *Collector {
+	return c.gauge(nil, func(values map[sample]float64) {
+		prev, curr := w.GetCount()
+		setWindowCounts(values, limiter, prev, curr, w.Peek(0).Limit)
+	})
+}
+
+// SyncSlidingWindow reads the window while holding mu, as SyncSlidingWindow is not safe for concurrent use. mu can
+// be nil if the window is not used while the metrics are rendered.
+func (c *Collector) SyncSlidingWindow(limiter string, w *strategy.SyncSlidingWindow, mu sync.Locker) *Collector {
+	return c.gauge(mu, func(values map[sample]float64) {
+		// peeking first moves the windows along to now
+		limit := w.Peek(0).Limit
+		prev, curr := w.Count()
+		setWindowCounts(values, limiter, prev, curr, limit)
+	})
+}
+
+func setWindowCounts(values map[sample]float64, limiter string, prev int, curr int, limit int) {
+	values[newSample("ratelimit_window_count", "limiter", limiter, "window", "previous")] = float64(prev)
+	values[newSample("ratelimit_window_count", "limiter", limiter, "window", "current")] = float64(curr)
+	values[newSample("ratelimit_capacity", "limiter", limiter)] = float64(limit)
+}
+
+func (c *Collector) gauge(mu sync.Locker, read func(values map[sample]float64)) *Collector {
+	c.Lock()
+	defer c.Unlock()
+
+	c.gauges = append(c.gauges, func(values map[sample]float64) {
+		if mu != nil {
+			mu.Lock()
+			defer mu.Unlock()
+		}
+		read(values)
+	})
+	return c
+}
+
+// WriteTo renders every metric that has a value.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.Lock()
+	values := make(map[sample]float64, len(c.values))
+	for s, v := range c.values {
+		values[s] = v
+	}
+	gauges := c.gauges
+	c.Unlock()
+
+	for _, read := range gauges {
+		read(values)
+	}
+
+	byMetric := make(map[string][]sample)
+	for s := range values {
+		byMetric[s.metric] = append(byMetric[s.metric], s)
+	}
+
+	var b strings.Builder
+	for _, m := range metrics {
+		samples := byMetric[m.name]
+		if len(samples) == 0 {
+			continue
+		}
+		sort.Slice(samples, func(i, j int) bool {
+			return samples[i].labels < samples[j].labels
+		})
+
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		for _, s := range samples {
+			fmt.Fprintf(&b, "%s{%s} %s\n", s.metric, s.labels, strconv.FormatFloat(values[s], 'g', -1, 64))
+		}
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+// newSample builds a sample from pairs of label names and values.
+func newSample(metric string, labels ...string) sample {
+	var b strings.Builder
+	for i := 0; i < len(labels); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", labels[i], escapeLabel(labels[i+1]))
+	}
+	return sample{metric: metric, labels: b.String()}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
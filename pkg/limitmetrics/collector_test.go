@@ -0,0 +1,93 @@
+package limitmetrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+	"github.com/edfoh/data-structures/pkg/limitmetrics"
+	"github.com/edfoh/data-structures/pkg/strategy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	collector := limitmetrics.NewCollector()
+
+	tokenBucket := datastruct.NewTokenBucket(5, 0)
+	limiter := strategy.NewObservedLimiter(strategy.NewTokenBucketLimiter(tokenBucket), collector.Observer("api"))
+	limiter.Take(3)
+	limiter.Take(3)
+
+	waits := collector.Observer(`client "b"`)
+	waits.Observe(strategy.Event{Kind: strategy.EventWait, Cost: 1, Wait: 1500 * time.Millisecond})
+
+	var mu sync.Mutex
+	leakyBucket := strategy.NewLeakyBucket(1, 1*time.Second, 10)
+	leakyBucket.Take(4)
+	queue := strategy.NewLeakyBucketChan[int](make(chan int), 3, 1)
+	queue.Enqueue(1)
+	window := strategy.NewSyncSlidingWindow(1*time.Hour, 10)
+	window.Take(2)
+
+	collector.
+		TokenBucket("api", tokenBucket).
+		LeakyBucket("uploads", leakyBucket, &mu).
+		LeakyBucketChan("jobs", queue).
+		SyncSlidingWindow("logins", window, &mu)
+
+	var b strings.Builder
+	_, err := collector.WriteTo(&b)
+	require.NoError(t, err)
+
+	assert.Equal(t, `# HELP ratelimit_events_total Events by limiter and kind of event.
+# TYPE ratelimit_events_total counter
+ratelimit_events_total{limiter="api",event="allow"} 1
+ratelimit_events_total{limiter="api",event="deny"} 1
+ratelimit_events_total{limiter="client \"b\"",event="wait"} 1
+# HELP ratelimit_cost_total Cost of the allowed and denied takes by limiter.
+# TYPE ratelimit_cost_total counter
+ratelimit_cost_total{limiter="api",event="allow"} 3
+ratelimit_cost_total{limiter="api",event="deny"} 3
+# HELP ratelimit_wait_seconds_total Time spent waiting for a limiter.
+# TYPE ratelimit_wait_seconds_total counter
+ratelimit_wait_seconds_total{limiter="client \"b\""} 1.5
+# HELP ratelimit_remaining Remaining capacity after the last take.
+# TYPE ratelimit_remaining gauge
+ratelimit_remaining{limiter="api"} 2
+# HELP ratelimit_capacity Capacity of the limiter.
+# TYPE ratelimit_capacity gauge
+ratelimit_capacity{limiter="api"} 5
+ratelimit_capacity{limiter="jobs"} 3
+ratelimit_capacity{limiter="logins"} 10
+ratelimit_capacity{limiter="uploads"} 10
+# HELP ratelimit_tokens Tokens in a token bucket.
+# TYPE ratelimit_tokens gauge
+ratelimit_tokens{limiter="api"} 2
+# HELP ratelimit_bucket_level Amount in a leaky bucket that has not leaked out yet.
+# TYPE ratelimit_bucket_level gauge
+ratelimit_bucket_level{limiter="uploads"} 4
+# HELP ratelimit_queue_depth Tasks waiting in a leaky bucket queue.
+# TYPE ratelimit_queue_depth gauge
+ratelimit_queue_depth{limiter="jobs"} 1
+# HELP ratelimit_window_count Count of the previous and current sliding windows.
+# TYPE ratelimit_window_count gauge
+ratelimit_window_count{limiter="logins",window="current"} 2
+ratelimit_window_count{limiter="logins",window="previous"} 0
+`, b.String())
+}
+
+func TestCollector_ServeHTTP(t *testing.T) {
+	collector := limitmetrics.NewCollector()
+	collector.Observer("api").Observe(strategy.Event{Kind: strategy.EventDeny, Cost: 1})
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `ratelimit_events_total{limiter="api",event="deny"} 1`)
+}
@@ -0,0 +1,140 @@
+package ratesim
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Arrival is a request arriving at a limiter.
+type Arrival struct {
+	Time time.Time
+	Key  string
+	Cost int
+}
+
+// ReadCSV reads a trace with a timestamp, a key and an optional cost on each line. Timestamps are either RFC 3339
+// or seconds since the Unix epoch, which can have a fraction. A first line that does not start with a timestamp
+// is treated as a header. The arrivals are returned in time order.
+func ReadCSV(r io.Reader) ([]Arrival, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	var arrivals []Arrival
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 2 || len(record) > 3 {
+			return nil, fmt.Errorf("line %d: expected timestamp,key[,cost], got %d fields", line, len(record))
+		}
+
+		t, err := parseTimestamp(record[0])
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		cost := 1
+		if len(record) == 3 {
+			if cost, err = parseCost(record[2]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		arrivals = append(arrivals, Arrival{Time: t, Key: record[1], Cost: cost})
+	}
+
+	sortArrivals(arrivals)
+	return arrivals, nil
+}
+
+type jsonArrival struct {
+	Time json.RawMessage `json:"time"`
+	Key  string          `json:"key"`
+	Cost *int            `json:"cost"`
+}
+
+// ReadJSONL reads a trace with a JSON object on each line, such as {"time": "2022-01-01T12:00:00Z", "key": "a",
+// "cost": 2}. The time is either an RFC 3339 string or seconds since the Unix epoch, and the cost defaults to 1.
+// The arrivals are returned in time order.
+func ReadJSONL(r io.Reader) ([]Arrival, error) {
+	scanner := bufio.NewScanner(r)
+
+	var arrivals []Arrival
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var a jsonArrival
+		if err := json.Unmarshal([]byte(text), &a); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		var value string
+		if err := json.Unmarshal(a.Time, &value); err != nil {
+			value = string(a.Time)
+		}
+		t, err := parseTimestamp(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		cost := 1
+		if a.Cost != nil {
+			if cost = *a.Cost; cost <= 0 {
+				return nil, fmt.Errorf("line %d: cost must be greater than 0, got %d", line, cost)
+			}
+		}
+		arrivals = append(arrivals, Arrival{Time: t, Key: a.Key, Cost: cost})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sortArrivals(arrivals)
+	return arrivals, nil
+}
+
+func parseTimestamp(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("timestamp is required")
+	}
+	if secs, err := strconv.ParseFloat(value, 64); err == nil {
+		whole, frac := math.Modf(secs)
+		return time.Unix(int64(whole), int64(frac*float64(time.Second))).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("timestamp %q is neither RFC 3339 nor seconds since the epoch", value)
+	}
+	return t, nil
+}
+
+func parseCost(value string) (int, error) {
+	cost, err := strconv.Atoi(value)
+	if err != nil || cost <= 0 {
+		return 0, fmt.Errorf("cost must be a number greater than 0, got %q", value)
+	}
+	return cost, nil
+}
+
+func sortArrivals(arrivals []Arrival) {
+	sort.SliceStable(arrivals, func(i, j int) bool {
+		return arrivals[i].Time.Before(arrivals[j].Time)
+	})
+}
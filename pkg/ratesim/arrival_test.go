@@ -0,0 +1,73 @@
+package ratesim
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCSV(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		data         string
+		wantArrivals []Arrival
+		wantErr      string
+	}{
+		{
+			desc: "header, epoch seconds and RFC 3339 are read in time order",
+			data: "timestamp,key,cost\n2022-01-01T12:00:01Z,b,2\n1641038400.5,a\n",
+			wantArrivals: []Arrival{
+				{Time: time.Date(2022, 1, 1, 12, 0, 0, 500000000, time.UTC), Key: "a", Cost: 1},
+				{Time: time.Date(2022, 1, 1, 12, 0, 1, 0, time.UTC), Key: "b", Cost: 2},
+			},
+		},
+		{
+			desc:    "invalid timestamp after the first line",
+			data:    "1,a\nyesterday,b\n",
+			wantErr: `line 2: timestamp "yesterday" is neither RFC 3339 nor seconds since the epoch`,
+		},
+		{
+			desc:    "invalid cost",
+			data:    "1,a,0\n",
+			wantErr: `line 1: cost must be a number greater than 0, got "0"`,
+		},
+		{
+			desc:    "missing key",
+			data:    "1\n",
+			wantErr: "line 1: expected timestamp,key[,cost], got 1 fields",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := ReadCSV(strings.NewReader(tC.data))
+
+			if tC.wantErr != "" {
+				assert.EqualError(t, err, tC.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tC.wantArrivals, got)
+		})
+	}
+}
+
+func TestReadJSONL(t *testing.T) {
+	data := `{"time": "2022-01-01T12:00:01Z", "key": "b", "cost": 3}
+
+{"time": 1641038400, "key": "a"}
+`
+
+	got, err := ReadJSONL(strings.NewReader(data))
+
+	require.NoError(t, err)
+	assert.Equal(t, []Arrival{
+		{Time: time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC), Key: "a", Cost: 1},
+		{Time: time.Date(2022, 1, 1, 12, 0, 1, 0, time.UTC), Key: "b", Cost: 3},
+	}, got)
+
+	_, err = ReadJSONL(strings.NewReader(`{"time": 1, "key": "a", "cost": -1}`))
+	assert.EqualError(t, err, "line 1: cost must be greater than 0, got -1")
+}
@@ -0,0 +1,130 @@
+package ratesim
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Process generates synthetic arrivals, spread evenly over a number of keys.
+type Process struct {
+	// Rate is the average number of arrivals per second.
+	Rate     float64
+	Duration time.Duration
+	// Start is when the first arrival can happen, the Unix epoch if it is zero.
+	Start time.Time
+	// Keys is how many keys the arrivals are spread over, 1 if it is zero.
+	Keys int
+	Seed int64
+}
+
+// Poisson generates arrivals with exponentially distributed gaps between them. It returns an error if the rate is
+// negative or not finite, the duration is not positive or the number of keys is negative.
+func (p Process) Poisson() ([]Arrival, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	r := rand.New(rand.NewSource(p.Seed))
+	return p.generate(r, func(time.Duration) float64 { return p.Rate }), nil
+}
+
+// Bursty generates Poisson arrivals along with a burst of size arrivals at the same instant every interval. It
+// returns an error for the same processes as Poisson, or if every is not positive or size is negative.
+func (p Process) Bursty(every time.Duration, size int) ([]Arrival, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	if every <= 0 {
+		return nil, errors.New("burst interval must be greater than 0")
+	}
+	if size < 0 {
+		return nil, errors.New("burst size must not be negative")
+	}
+	r := rand.New(rand.NewSource(p.Seed))
+	arrivals := p.generate(r, func(time.Duration) float64 { return p.Rate })
+	for offset := time.Duration(0); offset < p.Duration; offset += every {
+		for i := 0; i < size; i++ {
+			arrivals = append(arrivals, Arrival{Time: p.start().Add(offset), Key: p.key(r), Cost: 1})
+		}
+	}
+
+	sortArrivals(arrivals)
+	return arrivals, nil
+}
+
+// Diurnal generates Poisson arrivals whose rate follows a sine wave over the period, peaking at 1+amplitude times
+// the rate half way through and dropping to 1-amplitude times the rate at the start and end of each period. It
+// returns an error for the same processes as Poisson, or if period is not positive or amplitude is not from 0 to 1.
+func (p Process) Diurnal(period time.Duration, amplitude float64) ([]Arrival, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	if period <= 0 {
+		return nil, errors.New("period must be greater than 0")
+	}
+	if !(amplitude >= 0 && amplitude <= 1) {
+		return nil, fmt.Errorf("amplitude must be from 0 to 1, got %v", amplitude)
+	}
+	r := rand.New(rand.NewSource(p.Seed))
+	return p.generate(r, func(offset time.Duration) float64 {
+		phase := 2 * math.Pi * float64(offset%period) / float64(period)
+		return p.Rate * (1 - amplitude*math.Cos(phase))
+	}), nil
+}
+
+func (p Process) validate() error {
+	if !(p.Rate >= 0) || math.IsInf(p.Rate, 1) {
+		return fmt.Errorf("rate must be a finite number that is not negative, got %v", p.Rate)
+	}
+	if p.Duration <= 0 {
+		return errors.New("duration must be greater than 0")
+	}
+	if p.Keys < 0 {
+		return errors.New("keys must not be negative")
+	}
+	return nil
+}
+
+// generate draws arrivals with a rate that can change over time, by drawing them at the peak rate and keeping
+// each with the probability of the rate at that time over the peak.
+func (p Process) generate(r *rand.Rand, rate func(offset time.Duration) float64) []Arrival {
+	step := p.Duration / 1000
+	if step <= 0 {
+		step = 1
+	}
+	peak := 0.0
+	for offset := time.Duration(0); offset < p.Duration; offset += step {
+		peak = math.Max(peak, rate(offset))
+	}
+	if peak <= 0 {
+		return nil
+	}
+
+	var arrivals []Arrival
+	offset := time.Duration(0)
+	for {
+		offset += time.Duration(r.ExpFloat64() / peak * float64(time.Second))
+		if offset >= p.Duration {
+			return arrivals
+		}
+		if r.Float64()*peak < rate(offset) {
+			arrivals = append(arrivals, Arrival{Time: p.start().Add(offset), Key: p.key(r), Cost: 1})
+		}
+	}
+}
+
+func (p Process) start() time.Time {
+	if p.Start.IsZero() {
+		return time.Unix(0, 0).UTC()
+	}
+	return p.Start
+}
+
+func (p Process) key(r *rand.Rand) string {
+	if p.Keys <= 1 {
+		return "key-0"
+	}
+	return fmt.Sprintf("key-%d", r.Intn(p.Keys))
+}
@@ -0,0 +1,85 @@
+package ratesim
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcess(t *testing.T) {
+	p := Process{Rate: 10, Duration: 1 * time.Hour, Keys: 4, Seed: 42}
+
+	t.Run("poisson averages the rate", func(t *testing.T) {
+		arrivals, err := p.Poisson()
+		require.NoError(t, err)
+
+		assert.InDelta(t, 36000, len(arrivals), 1000)
+		again, _ := p.Poisson()
+		assert.Equal(t, arrivals, again, "the same seed generates the same arrivals")
+		keys := make(map[string]bool)
+		for _, a := range arrivals {
+			keys[a.Key] = true
+		}
+		assert.Len(t, keys, 4)
+	})
+
+	t.Run("bursty adds bursts at the same instant", func(t *testing.T) {
+		arrivals, err := p.Bursty(10*time.Minute, 100)
+		require.NoError(t, err)
+
+		assert.InDelta(t, 36600, len(arrivals), 1000)
+		atStart := 0
+		for _, a := range arrivals {
+			if a.Time.Equal(p.start().Add(30 * time.Minute)) {
+				atStart++
+			}
+		}
+		assert.Equal(t, 100, atStart)
+	})
+
+	t.Run("diurnal peaks in the middle of the period", func(t *testing.T) {
+		arrivals, err := p.Diurnal(1*time.Hour, 0.9)
+		require.NoError(t, err)
+
+		quarters := make([]int, 4)
+		for _, a := range arrivals {
+			quarters[a.Time.Sub(p.start())/(15*time.Minute)]++
+		}
+		assert.InDelta(t, 36000, len(arrivals), 1000)
+		assert.Greater(t, quarters[1], 2*quarters[0])
+		assert.Greater(t, quarters[2], 2*quarters[3])
+	})
+}
+
+func TestProcess_Invalid(t *testing.T) {
+	valid := Process{Rate: 10, Duration: 1 * time.Hour, Keys: 4}
+
+	testCases := []struct {
+		desc     string
+		generate func() ([]Arrival, error)
+	}{
+		{desc: "negative rate", generate: Process{Rate: -1, Duration: 1 * time.Hour}.Poisson},
+		{desc: "infinite rate", generate: Process{Rate: math.Inf(1), Duration: 1 * time.Hour}.Poisson},
+		{desc: "zero duration", generate: Process{Rate: 10}.Poisson},
+		{desc: "negative duration", generate: Process{Rate: 10, Duration: -1 * time.Hour}.Poisson},
+		{desc: "negative keys", generate: Process{Rate: 10, Duration: 1 * time.Hour, Keys: -1}.Poisson},
+		{desc: "zero burst interval", generate: func() ([]Arrival, error) { return valid.Bursty(0, 10) }},
+		{desc: "negative burst interval", generate: func() ([]Arrival, error) { return valid.Bursty(-1*time.Second, 10) }},
+		{desc: "negative burst size", generate: func() ([]Arrival, error) { return valid.Bursty(10*time.Second, -1) }},
+		{desc: "zero period", generate: func() ([]Arrival, error) { return valid.Diurnal(0, 0.5) }},
+		{desc: "negative period", generate: func() ([]Arrival, error) { return valid.Diurnal(-1*time.Hour, 0.5) }},
+		{desc: "amplitude over 1", generate: func() ([]Arrival, error) { return valid.Diurnal(1*time.Hour, 1.5) }},
+		{desc: "negative amplitude", generate: func() ([]Arrival, error) { return valid.Diurnal(1*time.Hour, -0.5) }},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			arrivals, err := tC.generate()
+
+			assert.Error(t, err)
+			assert.Nil(t, arrivals)
+		})
+	}
+}
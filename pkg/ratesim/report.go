@@ -0,0 +1,231 @@
+package ratesim
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// Stats are the outcomes of the requests of a key, or of all keys. The waits are of the accepted requests.
+type Stats struct {
+	Requests int
+	Accepted int
+	Rejected int
+	// Waited is how many accepted requests had to wait.
+	Waited   int
+	MeanWait time.Duration
+	P50Wait  time.Duration
+	P99Wait  time.Duration
+	MaxWait  time.Duration
+
+	waits []time.Duration
+}
+
+// Second is how many requests were accepted and rejected within a second of the simulation.
+type Second struct {
+	Start    time.Time
+	Accepted int
+	Rejected int
+}
+
+type Report struct {
+	Total Stats
+	Keys  map[string]*Stats
+	// Timeline has every second from the first request to the last, including seconds with no requests.
+	Timeline []Second
+	// PeakAccepted is the most requests accepted within a second of the timeline.
+	PeakAccepted int
+	// LongestRejectStreak is the most requests rejected in a row.
+	LongestRejectStreak int
+
+	seconds map[int64]*Second
+	streak  int
+}
+
+func newReport() *Report {
+	return &Report{
+		Keys:    make(map[string]*Stats),
+		seconds: make(map[int64]*Second),
+	}
+}
+
+func (r *Report) accept(a Arrival, t time.Time, waited time.Duration) {
+	for _, s := range []*Stats{&r.Total, r.key(a.Key)} {
+		s.Requests++
+		s.Accepted++
+		s.waits = append(s.waits, waited)
+		if waited > 0 {
+			s.Waited++
+		}
+	}
+	r.second(t).Accepted++
+	r.streak = 0
+}
+
+func (r *Report) reject(a Arrival, t time.Time) {
+	for _, s := range []*Stats{&r.Total, r.key(a.Key)} {
+		s.Requests++
+		s.Rejected++
+	}
+	r.second(t).Rejected++
+	r.streak++
+	if r.streak > r.LongestRejectStreak {
+		r.LongestRejectStreak = r.streak
+	}
+}
+
+func (r *Report) key(key string) *Stats {
+	s, ok := r.Keys[key]
+	if !ok {
+		s = &Stats{}
+		r.Keys[key] = s
+	}
+	return s
+}
+
+func (r *Report) second(t time.Time) *Second {
+	unix := t.Unix()
+	s, ok := r.seconds[unix]
+	if !ok {
+		s = &Second{Start: time.Unix(unix, 0).UTC()}
+		r.seconds[unix] = s
+	}
+	return s
+}
+
+func (r *Report) finish() {
+	r.Total.finish()
+	for _, s := range r.Keys {
+		s.finish()
+	}
+
+	if len(r.seconds) == 0 {
+		return
+	}
+	first, last := int64(math.MaxInt64), int64(math.MinInt64)
+	for unix := range r.seconds {
+		if unix < first {
+			first = unix
+		}
+		if unix > last {
+			last = unix
+		}
+	}
+	for unix := first; unix <= last; unix++ {
+		s, ok := r.seconds[unix]
+		if !ok {
+			s = &Second{Start: time.Unix(unix, 0).UTC()}
+		}
+		r.Timeline = append(r.Timeline, *s)
+		if s.Accepted > r.PeakAccepted {
+			r.PeakAccepted = s.Accepted
+		}
+	}
+}
+
+func (s *Stats) finish() {
+	if len(s.waits) == 0 {
+		return
+	}
+	sort.Slice(s.waits, func(i, j int) bool {
+		return s.waits[i] < s.waits[j]
+	})
+
+	var total time.Duration
+	for _, w := range s.waits {
+		total += w
+	}
+	s.MeanWait = total / time.Duration(len(s.waits))
+	s.P50Wait = percentile(s.waits, 50)
+	s.P99Wait = percentile(s.waits, 99)
+	s.MaxWait = s.waits[len(s.waits)-1]
+}
+
+// percentile uses the nearest rank of sorted.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+var columns = []string{"key", "requests", "accepted", "rejected", "waited", "mean_wait", "p50_wait", "p99_wait", "max_wait"}
+
+// rows returns the stats of every key in key order, followed by the total.
+func (r *Report) rows() [][]string {
+	keys := make([]string, 0, len(r.Keys))
+	for key := range r.Keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rows := make([][]string, 0, len(keys)+1)
+	for _, key := range keys {
+		rows = append(rows, r.Keys[key].row(key))
+	}
+	return append(rows, r.Total.row("total"))
+}
+
+func (s *Stats) row(key string) []string {
+	return []string{
+		key,
+		strconv.Itoa(s.Requests),
+		strconv.Itoa(s.Accepted),
+		strconv.Itoa(s.Rejected),
+		strconv.Itoa(s.Waited),
+		s.MeanWait.String(),
+		s.P50Wait.String(),
+		s.P99Wait.String(),
+		s.MaxWait.String(),
+	}
+}
+
+// WriteTable writes the stats of every key and the total as an aligned table, followed by the burst behaviour.
+func (r *Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	for _, row := range append([][]string{columns}, r.rows()...) {
+		for _, cell := range row {
+			fmt.Fprintf(tw, "%s\t", cell)
+		}
+		fmt.Fprintln(tw)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\npeak accepted per second: %d\nlongest reject streak: %d\n", r.PeakAccepted, r.LongestRejectStreak)
+	return err
+}
+
+// WriteCSV writes the stats of every key and the total, with a header.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(r.rows()); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// WriteTimelineCSV writes how many requests were accepted and rejected every second, with a header.
+func (r *Report) WriteTimelineCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"second", "accepted", "rejected"}); err != nil {
+		return err
+	}
+	for _, s := range r.Timeline {
+		if err := cw.Write([]string{s.Start.Format(time.RFC3339), strconv.Itoa(s.Accepted), strconv.Itoa(s.Rejected)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
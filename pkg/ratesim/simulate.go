@@ -0,0 +1,101 @@
+package ratesim
+
+import (
+	"container/heap"
+	"sync/atomic"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/strategy"
+)
+
+// Limiter takes n from the limit of a key, like limitconfig.Limit and strategy.KeyedLimiter.
+type Limiter interface {
+	Take(key string, n int) strategy.Result
+}
+
+// Run replays the arrivals through a limiter under a simulated clock, so that hours of traffic take as long as
+// the limiter takes to run. The limiter is created with newLimiter once the clock is at the first arrival.
+//
+// A rejected request waits for its retry time and tries again, as long as it does not wait longer than maxWait
+// in total. With a maxWait of 0 rejected requests give up straight away.
+//
+// Any limiter of the strategy package that implements strategy.Limiter can be simulated, e.g. wrapped with
+// strategy.KeyedLimiter or httplimit.Global. LeakyBucketChan is a queue that leaks on a real ticker rather than a
+// limiter, and cannot be simulated.
+//
+// Run replaces the clock of the strategy package while it runs, so no other limiters can be used at the same time.
+func Run(newLimiter func() Limiter, arrivals []Arrival, maxWait time.Duration) *Report {
+	report := newReport()
+	if len(arrivals) == 0 {
+		return report
+	}
+
+	// the clock is read by the goroutine of SlidingWindow, so it is moved along atomically
+	start := arrivals[0].Time
+	var elapsed atomic.Int64
+	restore := strategy.SetClock(func() time.Time { return start.Add(time.Duration(elapsed.Load())) })
+	defer restore()
+	limiter := newLimiter()
+
+	pending := make(attempts, 0, len(arrivals))
+	for i, a := range arrivals {
+		pending = append(pending, attempt{at: a.Time, arrival: a, seq: i})
+	}
+	heap.Init(&pending)
+
+	for pending.Len() > 0 {
+		next := heap.Pop(&pending).(attempt)
+		elapsed.Store(int64(next.at.Sub(start)))
+
+		res := limiter.Take(next.arrival.Key, next.arrival.Cost)
+		waited := next.at.Sub(next.arrival.Time)
+		switch {
+		case res.Allowed:
+			report.accept(next.arrival, next.at, waited)
+		case maxWait > 0 && res.RetryAfter > 0 && waited+res.RetryAfter <= maxWait:
+			next.at = next.at.Add(res.RetryAfter)
+			heap.Push(&pending, next)
+		default:
+			report.reject(next.arrival, next.at)
+		}
+	}
+	report.finish()
+	return report
+}
+
+type attempt struct {
+	at      time.Time
+	arrival Arrival
+	// seq keeps attempts at the same time in the order they arrived
+	seq int
+}
+
+// attempts is a min heap of attempts ordered by when they are made.
+type attempts []attempt
+
+func (a attempts) Len() int {
+	return len(a)
+}
+
+func (a attempts) Less(i, j int) bool {
+	if a[i].at.Equal(a[j].at) {
+		return a[i].seq < a[j].seq
+	}
+	return a[i].at.Before(a[j].at)
+}
+
+func (a attempts) Swap(i, j int) {
+	a[i], a[j] = a[j], a[i]
+}
+
+func (a *attempts) Push(x interface{}) {
+	*a = append(*a, x.(attempt))
+}
+
+func (a *attempts) Pop() interface{} {
+	old := *a
+	n := len(old)
+	x := old[n-1]
+	*a = old[:n-1]
+	return x
+}
@@ -0,0 +1,122 @@
+package ratesim
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/httplimit"
+	"github.com/edfoh/data-structures/pkg/strategy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newKeyedFixedWindow(capacity int) func() Limiter {
+	return func() Limiter {
+		return strategy.NewKeyedLimiter[string](func() strategy.Limiter {
+			return strategy.NewFixedWindow(1*time.Second, 0, capacity)
+		}, 1*time.Minute)
+	}
+}
+
+func TestRun(t *testing.T) {
+	start := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	arrivals := []Arrival{
+		{Time: start, Key: "a", Cost: 1},
+		{Time: start, Key: "a", Cost: 1},
+		{Time: start, Key: "a", Cost: 1},
+		{Time: start.Add(100 * time.Millisecond), Key: "b", Cost: 1},
+		{Time: start.Add(2 * time.Second), Key: "a", Cost: 1},
+	}
+
+	t.Run("rejected requests give up without a max wait", func(t *testing.T) {
+		report := Run(newKeyedFixedWindow(2), arrivals, 0)
+
+		assert.Equal(t, 5, report.Total.Requests)
+		assert.Equal(t, 4, report.Total.Accepted)
+		assert.Equal(t, 1, report.Total.Rejected)
+		assert.Equal(t, 1, report.Keys["a"].Rejected)
+		assert.Equal(t, 0, report.Keys["b"].Rejected)
+		assert.Equal(t, []Second{
+			{Start: start, Accepted: 3, Rejected: 1},
+			{Start: start.Add(1 * time.Second)},
+			{Start: start.Add(2 * time.Second), Accepted: 1},
+		}, report.Timeline)
+		assert.Equal(t, 3, report.PeakAccepted)
+		assert.Equal(t, 1, report.LongestRejectStreak)
+	})
+
+	t.Run("rejected requests wait for the next window", func(t *testing.T) {
+		report := Run(newKeyedFixedWindow(2), arrivals, 5*time.Second)
+
+		assert.Equal(t, 5, report.Total.Accepted)
+		assert.Equal(t, 1, report.Total.Waited)
+		assert.Equal(t, 200*time.Millisecond, report.Total.MeanWait)
+		assert.Equal(t, time.Duration(0), report.Total.P50Wait)
+		assert.Equal(t, 1*time.Second, report.Total.MaxWait)
+	})
+
+	t.Run("requests that would wait too long are rejected", func(t *testing.T) {
+		report := Run(newKeyedFixedWindow(2), arrivals, 500*time.Millisecond)
+
+		assert.Equal(t, 1, report.Total.Rejected)
+	})
+
+	t.Run("the clock is restored", func(t *testing.T) {
+		w := strategy.NewFixedWindow(1*time.Hour, 0, 1)
+
+		assert.WithinDuration(t, time.Now(), w.ResetTime(), 1*time.Hour)
+	})
+}
+
+func TestRun_SlidingWindow(t *testing.T) {
+	start := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	var w *strategy.SlidingWindow
+	newLimiter := func() Limiter {
+		w = strategy.NewSlidingWindow(1*time.Hour, 2)
+		return httplimit.Global(w)
+	}
+
+	report := Run(newLimiter, []Arrival{
+		{Time: start, Key: "a", Cost: 2},
+		{Time: start.Add(30 * time.Minute), Key: "a", Cost: 2},
+		{Time: start.Add(170 * time.Minute), Key: "a", Cost: 2},
+	}, 0)
+	w.Stop()
+
+	// the windows slide by the simulated clock, long before the real time of the interval has passed
+	assert.Equal(t, 2, report.Total.Accepted)
+	assert.Equal(t, 1, report.Total.Rejected)
+}
+
+func TestReport_Write(t *testing.T) {
+	start := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	report := Run(newKeyedFixedWindow(1), []Arrival{
+		{Time: start, Key: "a", Cost: 1},
+		{Time: start, Key: "a", Cost: 1},
+	}, 0)
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, report.WriteCSV(&buf))
+
+		assert.Equal(t, `key,requests,accepted,rejected,waited,mean_wait,p50_wait,p99_wait,max_wait
+a,2,1,1,0,0s,0s,0s,0s
+total,2,1,1,0,0s,0s,0s,0s
+`, buf.String())
+	})
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, report.WriteTable(&buf))
+
+		assert.Contains(t, buf.String(), "peak accepted per second: 1\nlongest reject streak: 1\n")
+	})
+
+	t.Run("timeline", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, report.WriteTimelineCSV(&buf))
+
+		assert.Equal(t, "second,accepted,rejected\n2022-01-01T12:00:00Z,1,1\n", buf.String())
+	})
+}
@@ -0,0 +1,236 @@
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Error is an error reply from the server.
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// Client talks to a Redis compatible server using the RESP protocol. It implements strategy.Store, using
+// WATCH and MULTI/EXEC for compare and swap. Connections are pooled and dialled when needed.
+type Client struct {
+	sync.Mutex
+	addr string
+	idle []*conn
+}
+
+func NewClient(addr string) *Client {
+	return &Client{
+		addr: addr,
+	}
+}
+
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := c.withConn(ctx, func(cn *conn) error {
+		reply, err := cn.do("GET", key)
+		if err != nil {
+			return err
+		}
+		value, err = bulk(reply)
+		return err
+	})
+	return value, err
+}
+
+func (c *Client) CompareAndSwap(ctx context.Context, key string, old []byte, new []byte, ttl time.Duration) (bool, error) {
+	var swapped bool
+	err := c.withConn(ctx, func(cn *conn) error {
+		if _, err := cn.do("WATCH", key); err != nil {
+			return err
+		}
+
+		reply, err := cn.do("GET", key)
+		if err != nil {
+			return err
+		}
+		curr, err := bulk(reply)
+		if err != nil {
+			return err
+		}
+		if (curr == nil) != (old == nil) || !bytes.Equal(curr, old) {
+			_, err := cn.do("UNWATCH")
+			return err
+		}
+
+		if _, err := cn.do("MULTI"); err != nil {
+			return err
+		}
+		args := []string{"SET", key, string(new)}
+		if ttl > 0 {
+			args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+		}
+		if _, err := cn.do(args...); err != nil {
+			return err
+		}
+		// EXEC replies with a nil array if the key changed since WATCH
+		reply, err = cn.do("EXEC")
+		if err != nil {
+			return err
+		}
+		swapped = reply != nil
+		return nil
+	})
+	return swapped, err
+}
+
+func (c *Client) Close() error {
+	c.Lock()
+	defer c.Unlock()
+
+	var err error
+	for _, cn := range c.idle {
+		if closeErr := cn.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}
+	c.idle = nil
+	return err
+}
+
+// withConn runs fn with a connection that nobody else is using, as WATCH and MULTI apply to the connection.
+// Connections are only reused if fn did not fail, since a failure can leave a connection mid transaction.
+func (c *Client) withConn(ctx context.Context, fn func(cn *conn) error) error {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	deadline, _ := ctx.Deadline()
+	if err := cn.SetDeadline(deadline); err != nil {
+		cn.Close()
+		return err
+	}
+
+	if err := fn(cn); err != nil {
+		cn.Close()
+		return err
+	}
+	c.put(cn)
+	return nil
+}
+
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	c.Lock()
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.Unlock()
+		return cn, nil
+	}
+	c.Unlock()
+
+	var d net.Dialer
+	nc, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: nc, r: bufio.NewReader(nc)}, nil
+}
+
+func (c *Client) put(cn *conn) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.idle = append(c.idle, cn)
+}
+
+// do sends a command and reads its reply. Error replies are returned as Error.
+func (cn *conn) do(args ...string) (interface{}, error) {
+	if _, err := cn.Write(Encode(args...)); err != nil {
+		return nil, err
+	}
+	reply, err := Read(cn.r)
+	if err != nil {
+		return nil, err
+	}
+	if replyErr, ok := reply.(Error); ok {
+		return nil, replyErr
+	}
+	return reply, nil
+}
+
+// Encode encodes a command as an array of bulk strings.
+func Encode(args ...string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&buf, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	return buf.Bytes()
+}
+
+// Read reads a single reply. Simple strings are returned as string, errors as Error, integers as int64, bulk
+// strings as []byte and arrays as []interface{}. Nil bulk strings and nil arrays are returned as nil.
+func Read(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed reply %q", line)
+	}
+	kind, line := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return line, nil
+	case '-':
+		return Error(line), nil
+	case ':':
+		return strconv.ParseInt(line, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line)
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	case '*':
+		size, err := strconv.Atoi(line)
+		if err != nil || size < 0 {
+			return nil, err
+		}
+		arr := make([]interface{}, size)
+		for i := range arr {
+			if arr[i], err = Read(r); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	default:
+		return nil, fmt.Errorf("unknown reply type %q", kind)
+	}
+}
+
+func bulk(reply interface{}) ([]byte, error) {
+	switch v := reply.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("expected bulk string reply, got %T", reply)
+	}
+}
@@ -0,0 +1,278 @@
+package resp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/strategy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ strategy.Store = (*Client)(nil)
+
+type fakeEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// fakeServer implements the handful of commands the client uses, including WATCH and MULTI/EXEC.
+type fakeServer struct {
+	sync.Mutex
+	listener net.Listener
+	data     map[string]fakeEntry
+	versions map[string]int
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &fakeServer{
+		listener: listener,
+		data:     make(map[string]fakeEntry),
+		versions: make(map[string]int),
+	}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *fakeServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeServer) serve() {
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(c)
+	}
+}
+
+func (s *fakeServer) handle(c net.Conn) {
+	defer c.Close()
+
+	r := bufio.NewReader(c)
+	watched := make(map[string]int)
+	var queued [][]string
+	inMulti := false
+
+	for {
+		req, err := Read(r)
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, arg := range req.([]interface{}) {
+			args = append(args, string(arg.([]byte)))
+		}
+
+		cmd := strings.ToUpper(args[0])
+		var reply string
+		switch {
+		case inMulti && cmd != "EXEC":
+			queued = append(queued, args)
+			reply = "+QUEUED\r\n"
+		case cmd == "MULTI":
+			inMulti = true
+			reply = "+OK\r\n"
+		case cmd == "EXEC":
+			reply = s.exec(watched, queued)
+			watched = make(map[string]int)
+			queued = nil
+			inMulti = false
+		case cmd == "WATCH":
+			s.Lock()
+			for _, key := range args[1:] {
+				watched[key] = s.versions[key]
+			}
+			s.Unlock()
+			reply = "+OK\r\n"
+		case cmd == "UNWATCH":
+			watched = make(map[string]int)
+			reply = "+OK\r\n"
+		default:
+			s.Lock()
+			reply = s.run(args)
+			s.Unlock()
+		}
+
+		if _, err := c.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeServer) exec(watched map[string]int, queued [][]string) string {
+	s.Lock()
+	defer s.Unlock()
+
+	for key, version := range watched {
+		if s.versions[key] != version {
+			return "*-1\r\n"
+		}
+	}
+	replies := fmt.Sprintf("*%d\r\n", len(queued))
+	for _, args := range queued {
+		replies += s.run(args)
+	}
+	return replies
+}
+
+func (s *fakeServer) run(args []string) string {
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		e, ok := s.data[args[1]]
+		if !ok || (!e.expiresAt.IsZero() && !time.Now().Before(e.expiresAt)) {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(e.value), e.value)
+	case "SET":
+		e := fakeEntry{value: args[2]}
+		if len(args) == 5 && strings.ToUpper(args[3]) == "PX" {
+			ms, _ := strconv.Atoi(args[4])
+			e.expiresAt = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		}
+		s.data[args[1]] = e
+		s.versions[args[1]]++
+		return "+OK\r\n"
+	default:
+		return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+func TestClient_CompareAndSwap(t *testing.T) {
+	server := newFakeServer(t)
+	client := NewClient(server.Addr())
+	defer client.Close()
+	ctx := context.Background()
+
+	t.Run("get a missing key returns nil", func(t *testing.T) {
+		got, err := client.Get(ctx, "k")
+
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("swap from nil creates the key", func(t *testing.T) {
+		swapped, err := client.CompareAndSwap(ctx, "k", nil, []byte("1"), 1*time.Minute)
+		require.NoError(t, err)
+		assert.True(t, swapped)
+
+		got, err := client.Get(ctx, "k")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("1"), got)
+	})
+
+	t.Run("swap with a stale value fails", func(t *testing.T) {
+		swapped, err := client.CompareAndSwap(ctx, "k", []byte("0"), []byte("2"), 1*time.Minute)
+		require.NoError(t, err)
+		assert.False(t, swapped)
+
+		got, err := client.Get(ctx, "k")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("1"), got)
+	})
+
+	t.Run("swap with the current value works", func(t *testing.T) {
+		swapped, err := client.CompareAndSwap(ctx, "k", []byte("1"), []byte("2"), 1*time.Minute)
+		require.NoError(t, err)
+		assert.True(t, swapped)
+
+		got, err := client.Get(ctx, "k")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("2"), got)
+	})
+
+	t.Run("keys expire after the ttl", func(t *testing.T) {
+		swapped, err := client.CompareAndSwap(ctx, "short", nil, []byte("1"), 1*time.Millisecond)
+		require.NoError(t, err)
+		require.True(t, swapped)
+
+		time.Sleep(5 * time.Millisecond)
+
+		got, err := client.Get(ctx, "short")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("keys without a ttl do not expire", func(t *testing.T) {
+		swapped, err := client.CompareAndSwap(ctx, "forever", nil, []byte("1"), 0)
+		require.NoError(t, err)
+		require.True(t, swapped)
+
+		time.Sleep(5 * time.Millisecond)
+
+		got, err := client.Get(ctx, "forever")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("1"), got)
+	})
+}
+
+func TestClient_SharedBudget(t *testing.T) {
+	server := newFakeServer(t)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for replica := 0; replica < 4; replica++ {
+		wg.Add(1)
+		client := NewClient(server.Addr())
+		defer client.Close()
+		limiter := strategy.NewStoreTokenBucket(client, "ratelimit:", 20, 0)
+
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				res, err := limiter.Take(context.Background(), "tenant", 1)
+				assert.NoError(t, err)
+				if res.Allowed {
+					mu.Lock()
+					allowed++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 20, allowed)
+}
+
+func TestRead(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		reply     string
+		wantReply interface{}
+	}{
+		{desc: "simple string", reply: "+OK\r\n", wantReply: "OK"},
+		{desc: "error", reply: "-ERR bad\r\n", wantReply: Error("ERR bad")},
+		{desc: "integer", reply: ":42\r\n", wantReply: int64(42)},
+		{desc: "bulk string", reply: "$5\r\nhello\r\n", wantReply: []byte("hello")},
+		{desc: "nil bulk string", reply: "$-1\r\n", wantReply: nil},
+		{desc: "array", reply: "*2\r\n+OK\r\n:1\r\n", wantReply: []interface{}{"OK", int64(1)}},
+		{desc: "nil array", reply: "*-1\r\n", wantReply: nil},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := Read(bufio.NewReader(strings.NewReader(tC.reply)))
+
+			require.NoError(t, err)
+			assert.Equal(t, tC.wantReply, got)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package strategy
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Sample is what was observed when a request completed.
+type Sample struct {
+	RTT time.Duration
+	// Inflight is the number of requests in flight when the request started, including itself.
+	Inflight int
+	// Dropped is set if the request failed, which is treated as a sign of overload.
+	Dropped bool
+}
+
+// LimitPolicy works out the next concurrency limit from the current limit and a completed request.
+type LimitPolicy interface {
+	Update(limit float64, sample Sample) float64
+}
+
+// AIMDPolicy grows the limit by 1 for every request that completes within the latency threshold, and multiplies
+// it by the backoff ratio when a request is dropped or too slow.
+type AIMDPolicy struct {
+	backoffRatio     float64
+	latencyThreshold time.Duration
+}
+
+func NewAIMDPolicy(backoffRatio float64, latencyThreshold time.Duration) *AIMDPolicy {
+	return &AIMDPolicy{
+		backoffRatio:     backoffRatio,
+		latencyThreshold: latencyThreshold,
+	}
+}
+
+func (p *AIMDPolicy) Update(limit float64, sample Sample) float64 {
+	if sample.Dropped || sample.RTT > p.latencyThreshold {
+		return limit * p.backoffRatio
+	}
+	// only grow if the limit is actually being used, otherwise it grows without bound while idle
+	if float64(sample.Inflight)*2 >= limit {
+		return limit + 1
+	}
+	return limit
+}
+
+// GradientPolicy is a delay based policy similar to TCP Vegas. The ratio of the lowest RTT seen to the latest
+// RTT estimates how much queueing there is downstream: a ratio of 1 means no queueing and the limit can grow by
+// a queue allowance of sqrt(limit), a lower ratio shrinks the limit in proportion. The smoothing factor between
+// 0 and 1 controls how quickly the limit moves towards the new estimate.
+type GradientPolicy struct {
+	smoothing float64
+	minRTT    time.Duration
+}
+
+func NewGradientPolicy(smoothing float64) *GradientPolicy {
+	return &GradientPolicy{
+		smoothing: smoothing,
+	}
+}
+
+// Update ignores the RTT of samples where it is not positive, e.g. from a coarse or fake clock, as it says nothing
+// about queueing and would make the gradient NaN.
+func (p *GradientPolicy) Update(limit float64, sample Sample) float64 {
+	if sample.RTT > 0 && (p.minRTT == 0 || sample.RTT < p.minRTT) {
+		p.minRTT = sample.RTT
+	}
+
+	gradient := 0.5
+	if !sample.Dropped {
+		if sample.RTT <= 0 {
+			return limit
+		}
+		gradient = math.Max(0.5, math.Min(1, float64(p.minRTT)/float64(sample.RTT)))
+	}
+	newLimit := limit*gradient + math.Sqrt(limit)
+
+	return limit*(1-p.smoothing) + newLimit*p.smoothing
+}
+
+// AdaptiveLimiter bounds the number of requests in flight, adjusting the bound from the latency and errors of
+// completed requests with a LimitPolicy.
+type AdaptiveLimiter struct {
+	sync.Mutex
+	policy   LimitPolicy
+	limit    float64
+	minLimit int
+	maxLimit int
+	inflight int
+}
+
+func NewAdaptiveLimiter(initialLimit int, minLimit int, maxLimit int, policy LimitPolicy) *AdaptiveLimiter {
+	return &AdaptiveLimiter{
+		policy:   policy,
+		limit:    float64(initialLimit),
+		minLimit: minLimit,
+		maxLimit: maxLimit,
+	}
+}
+
+func (l *AdaptiveLimiter) Limit() int {
+	l.Lock()
+	defer l.Unlock()
+
+	return int(l.limit)
+}
+
+func (l *AdaptiveLimiter) Inflight() int {
+	l.Lock()
+	defer l.Unlock()
+
+	return l.inflight
+}
+
+// Acquire takes an in-flight slot if the limit allows it. The returned permit has to be released once the
+// request completes.
+func (l *AdaptiveLimiter) Acquire() (*Permit, bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.inflight >= int(l.limit) {
+		return nil, false
+	}
+	l.inflight++
+	return &Permit{
+		limiter:  l,
+		start:    now(),
+		inflight: l.inflight,
+	}, true
+}
+
+func (l *AdaptiveLimiter) release(sample Sample) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.inflight--
+	limit := l.policy.Update(l.limit, sample)
+	l.limit = math.Max(float64(l.minLimit), math.Min(float64(l.maxLimit), limit))
+}
+
+// Permit is an in-flight slot taken from an AdaptiveLimiter.
+type Permit struct {
+	sync.Mutex
+	limiter  *AdaptiveLimiter
+	start    time.Time
+	inflight int
+	released bool
+}
+
+// Release gives the slot back and feeds the request's latency to the limit policy. A non-nil err marks the
+// request as dropped. Releasing more than once does nothing.
+func (p *Permit) Release(err error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.released {
+		return
+	}
+	p.released = true
+	p.limiter.release(Sample{
+		RTT:      now().Sub(p.start),
+		Inflight: p.inflight,
+		Dropped:  err != nil,
+	})
+}
@@ -0,0 +1,201 @@
+package strategy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptiveLimiter_Acquire(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	limiter := NewAdaptiveLimiter(2, 1, 10, NewAIMDPolicy(0.5, 100*time.Millisecond))
+
+	first, ok := limiter.Acquire()
+	require.True(t, ok)
+	second, ok := limiter.Acquire()
+	require.True(t, ok)
+
+	t.Run("acquire over the limit fails", func(t *testing.T) {
+		_, ok := limiter.Acquire()
+
+		assert.False(t, ok)
+		assert.Equal(t, 2, limiter.Inflight())
+	})
+
+	t.Run("fast release grows the limit", func(t *testing.T) {
+		setFakeNow(fakeNow.Add(10 * time.Millisecond))
+		first.Release(nil)
+
+		assert.Equal(t, 3, limiter.Limit())
+		assert.Equal(t, 1, limiter.Inflight())
+	})
+
+	t.Run("releasing twice does nothing", func(t *testing.T) {
+		first.Release(nil)
+
+		assert.Equal(t, 3, limiter.Limit())
+		assert.Equal(t, 1, limiter.Inflight())
+	})
+
+	t.Run("dropped release backs off the limit", func(t *testing.T) {
+		second.Release(errors.New("timeout"))
+
+		assert.Equal(t, 1, limiter.Limit())
+		assert.Equal(t, 0, limiter.Inflight())
+	})
+}
+
+func TestAIMDPolicy_Update(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		limit     float64
+		sample    Sample
+		wantLimit float64
+	}{
+		{
+			desc:      "fast request with the limit in use grows by 1",
+			limit:     10,
+			sample:    Sample{RTT: 50 * time.Millisecond, Inflight: 5},
+			wantLimit: 11,
+		},
+		{
+			desc:      "fast request with the limit mostly unused stays the same",
+			limit:     10,
+			sample:    Sample{RTT: 50 * time.Millisecond, Inflight: 4},
+			wantLimit: 10,
+		},
+		{
+			desc:      "slow request backs off",
+			limit:     10,
+			sample:    Sample{RTT: 150 * time.Millisecond, Inflight: 10},
+			wantLimit: 8,
+		},
+		{
+			desc:      "dropped request backs off",
+			limit:     10,
+			sample:    Sample{RTT: 50 * time.Millisecond, Inflight: 10, Dropped: true},
+			wantLimit: 8,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			policy := NewAIMDPolicy(0.8, 100*time.Millisecond)
+
+			assert.Equal(t, tC.wantLimit, policy.Update(tC.limit, tC.sample))
+		})
+	}
+}
+
+func TestGradientPolicy_Update(t *testing.T) {
+	policy := NewGradientPolicy(1)
+
+	t.Run("no queueing grows by sqrt of the limit", func(t *testing.T) {
+		assert.Equal(t, 20.0, policy.Update(16, Sample{RTT: 10 * time.Millisecond, Inflight: 16}))
+	})
+
+	t.Run("doubled latency halves the limit before adding the queue allowance", func(t *testing.T) {
+		assert.Equal(t, 12.0, policy.Update(16, Sample{RTT: 20 * time.Millisecond, Inflight: 16}))
+	})
+
+	t.Run("dropped request halves the limit", func(t *testing.T) {
+		assert.Equal(t, 12.0, policy.Update(16, Sample{RTT: 10 * time.Millisecond, Inflight: 16, Dropped: true}))
+	})
+
+	t.Run("zero RTT leaves the limit and the lowest RTT as they are", func(t *testing.T) {
+		assert.Equal(t, 16.0, policy.Update(16, Sample{RTT: 0, Inflight: 16}))
+		assert.Equal(t, 20.0, policy.Update(16, Sample{RTT: 10 * time.Millisecond, Inflight: 16}))
+	})
+
+	t.Run("dropped request with zero RTT still halves the limit", func(t *testing.T) {
+		assert.Equal(t, 12.0, policy.Update(16, Sample{RTT: 0, Inflight: 16, Dropped: true}))
+	})
+}
+
+// TestAdaptiveLimiter_Simulation overloads a synthetic service that can process capacity requests at once in
+// baseRTT. Any more requests queue, so latency grows in proportion to how far over capacity it is. Both policies
+// should settle within a factor of 2 of the capacity, rather than staying at the initial limit or growing to the
+// max limit.
+func TestAdaptiveLimiter_Simulation(t *testing.T) {
+	const (
+		capacity        = 50
+		baseRTT         = 10 * time.Millisecond
+		step            = 1 * time.Millisecond
+		arrivalsPerStep = 3 * capacity * int(step) / int(baseRTT)
+		duration        = 20 * time.Second
+	)
+	testCases := []struct {
+		desc    string
+		policy  LimitPolicy
+		wantMin int
+		wantMax int
+	}{
+		{
+			desc:    "AIMD settles near the capacity",
+			policy:  NewAIMDPolicy(0.9, 3*baseRTT/2),
+			wantMin: capacity / 2,
+			wantMax: 2 * capacity,
+		},
+		{
+			desc:    "gradient settles near the capacity",
+			policy:  NewGradientPolicy(0.2),
+			wantMin: capacity / 2,
+			wantMax: 2 * capacity,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			teardown := fakeTimeSetup(t)
+			defer teardown()
+
+			type request struct {
+				permit *Permit
+				doneAt time.Time
+			}
+			limiter := NewAdaptiveLimiter(10, 1, 1000, tC.policy)
+			var pending []request
+			var limitSum, limitSamples int
+
+			for elapsed := time.Duration(0); elapsed < duration; elapsed += step {
+				tNow := fakeNow.Add(elapsed)
+				setFakeNow(tNow)
+
+				var stillPending []request
+				for _, r := range pending {
+					if r.doneAt.After(tNow) {
+						stillPending = append(stillPending, r)
+						continue
+					}
+					r.permit.Release(nil)
+				}
+				pending = stillPending
+
+				for i := 0; i < arrivalsPerStep; i++ {
+					permit, ok := limiter.Acquire()
+					if !ok {
+						break
+					}
+					rtt := baseRTT
+					if inflight := limiter.Inflight(); inflight > capacity {
+						rtt = baseRTT * time.Duration(inflight) / capacity
+					}
+					pending = append(pending, request{permit: permit, doneAt: tNow.Add(rtt)})
+				}
+
+				// only measure once the limit has had time to settle
+				if elapsed > duration/2 {
+					limitSum += limiter.Limit()
+					limitSamples++
+				}
+			}
+
+			avgLimit := limitSum / limitSamples
+			assert.GreaterOrEqual(t, avgLimit, tC.wantMin)
+			assert.LessOrEqual(t, avgLimit, tC.wantMax)
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package strategy
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by a CircuitBreaker that is not letting calls through.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type BreakerState int
+
+const (
+	// BreakerClosed lets every call through and counts their failures.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects every call until the open timeout has passed.
+	BreakerOpen
+	// BreakerHalfOpen lets a limited number of probe calls through to find out if the failures have stopped.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker stops calls to something that keeps failing. While closed it counts calls and failures over a
+// sliding window, and opens once at least minCalls were made and the ratio of failures reaches tripRatio. After
+// openTimeout it goes half-open and lets up to probes calls through: it closes again if they all succeed, and opens
+// again as soon as one fails.
+type CircuitBreaker struct {
+	sync.Mutex
+	state         BreakerState
+	generation    int
+	calls         rollingCounter
+	failures      rollingCounter
+	interval      time.Duration
+	tripRatio     float64
+	minCalls      int
+	openTimeout   time.Duration
+	openedAt      time.Time
+	probes        int
+	probesStarted int
+	probesPassed  int
+	onStateChange func(from BreakerState, to BreakerState)
+	changes       [][2]BreakerState
+}
+
+func NewCircuitBreaker(interval time.Duration, tripRatio float64, minCalls int, openTimeout time.Duration, probes int) *CircuitBreaker {
+	return &CircuitBreaker{
+		calls:       newRollingCounter(interval),
+		failures:    newRollingCounter(interval),
+		interval:    interval,
+		tripRatio:   tripRatio,
+		minCalls:    minCalls,
+		openTimeout: openTimeout,
+		probes:      max(1, probes),
+	}
+}
+
+// OnStateChange calls fn every time the state changes. It is called after the breaker is unlocked, so it can
+// call back into the breaker.
+func (b *CircuitBreaker) OnStateChange(fn func(from BreakerState, to BreakerState)) *CircuitBreaker {
+	b.Lock()
+	defer b.Unlock()
+
+	b.onStateChange = fn
+	return b
+}
+
+func (b *CircuitBreaker) State() BreakerState {
+	b.Lock()
+	b.update(now())
+	state := b.state
+	b.unlock()
+
+	return state
+}
+
+// Allow returns ErrCircuitOpen if the call must not be made. Otherwise, the returned call has to be finished with
+// its outcome once it completes.
+func (b *CircuitBreaker) Allow() (*BreakerCall, error) {
+	b.Lock()
+	defer b.unlock()
+
+	b.update(now())
+	switch b.state {
+	case BreakerOpen:
+		return nil, ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.probesStarted >= b.probes {
+			return nil, ErrCircuitOpen
+		}
+		b.probesStarted++
+	}
+	return &BreakerCall{breaker: b, generation: b.generation}, nil
+}
+
+// Execute runs fn if the breaker allows it, and records whether it returned an error.
+func (b *CircuitBreaker) Execute(fn func() error) error {
+	call, err := b.Allow()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	call.Finish(err)
+	return err
+}
+
+func (b *CircuitBreaker) finish(generation int, failed bool) {
+	b.Lock()
+	defer b.unlock()
+
+	tNow := now()
+	b.update(tNow)
+	// calls that started before the last state change say nothing about the current state
+	if generation != b.generation {
+		return
+	}
+
+	switch b.state {
+	case BreakerClosed:
+		b.calls.add(tNow)
+		if failed {
+			b.failures.add(tNow)
+		}
+		calls := b.calls.count(tNow)
+		if failed && calls >= float64(b.minCalls) && b.failures.count(tNow)/calls >= b.tripRatio {
+			b.open(tNow)
+		}
+	case BreakerHalfOpen:
+		if failed {
+			b.open(tNow)
+			return
+		}
+		b.probesPassed++
+		if b.probesPassed >= b.probes {
+			b.setState(BreakerClosed)
+			b.calls = newRollingCounter(b.interval)
+			b.failures = newRollingCounter(b.interval)
+		}
+	}
+}
+
+// update moves an open breaker to half-open once the open timeout has passed.
+func (b *CircuitBreaker) update(t time.Time) {
+	if b.state == BreakerOpen && t.Sub(b.openedAt) >= b.openTimeout {
+		b.probesStarted = 0
+		b.probesPassed = 0
+		b.setState(BreakerHalfOpen)
+	}
+}
+
+func (b *CircuitBreaker) open(t time.Time) {
+	b.openedAt = t
+	b.setState(BreakerOpen)
+}
+
+func (b *CircuitBreaker) setState(state BreakerState) {
+	b.changes = append(b.changes, [2]BreakerState{b.state, state})
+	b.state = state
+	b.generation++
+}
+
+// unlock unlocks the breaker and then calls the callback with the state changes made while it was locked.
+func (b *CircuitBreaker) unlock() {
+	changes, fn := b.changes, b.onStateChange
+	b.changes = nil
+	b.Unlock()
+
+	if fn != nil {
+		for _, c := range changes {
+			fn(c[0], c[1])
+		}
+	}
+}
+
+// BreakerCall is a call let through by a CircuitBreaker.
+type BreakerCall struct {
+	sync.Mutex
+	breaker    *CircuitBreaker
+	generation int
+	finished   bool
+}
+
+// Finish records the outcome of the call, a non-nil err being a failure. Finishing more than once does nothing.
+func (c *BreakerCall) Finish(err error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.finished {
+		return
+	}
+	c.finished = true
+	c.breaker.finish(c.generation, err != nil)
+}
+
+// rollingCounter counts over a sliding window, using the windows of a SyncSlidingWindow to slide the same way.
+// Unlike SyncSlidingWindow, the current window counts in full, so that a breaker can trip as soon as a window
+// starts.
+type rollingCounter struct {
+	w *SyncSlidingWindow
+}
+
+func newRollingCounter(interval time.Duration) rollingCounter {
+	return rollingCounter{w: NewSyncSlidingWindow(interval, math.MaxInt)}
+}
+
+func (c rollingCounter) add(t time.Time) {
+	c.w.adjustWindows(t)
+	c.w.curr.AddN(1)
+}
+
+func (c rollingCounter) count(t time.Time) float64 {
+	c.w.adjustWindows(t)
+	elapsed := t.Sub(c.w.curr.StartTime())
+	prevWeight := float64(c.w.interval-elapsed) / float64(c.w.interval)
+	return float64(c.w.curr.Count()) + prevWeight*float64(c.w.prev.Count())
+}
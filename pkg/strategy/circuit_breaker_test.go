@@ -0,0 +1,155 @@
+package strategy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errCallFailed = errors.New("call failed")
+
+func succeed() error { return nil }
+
+func fail() error { return errCallFailed }
+
+func TestCircuitBreaker(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	var changes []string
+	breaker := NewCircuitBreaker(1*time.Minute, 0.5, 4, 10*time.Second, 2).
+		OnStateChange(func(from BreakerState, to BreakerState) {
+			changes = append(changes, from.String()+"->"+to.String())
+		})
+
+	testCases := []struct {
+		desc            string
+		fakeTimeElapsed time.Duration
+		calls           []func() error
+		wantErrs        []error
+		wantState       BreakerState
+	}{
+		{
+			desc:            "failures below the minimum calls do not trip",
+			fakeTimeElapsed: 0,
+			calls:           []func() error{fail, fail, fail},
+			wantErrs:        []error{errCallFailed, errCallFailed, errCallFailed},
+			wantState:       BreakerClosed,
+		},
+		{
+			desc:            "reaching the trip ratio with enough calls opens",
+			fakeTimeElapsed: 1 * time.Second,
+			calls:           []func() error{fail, succeed},
+			wantErrs:        []error{errCallFailed, ErrCircuitOpen},
+			wantState:       BreakerOpen,
+		},
+		{
+			desc:            "open rejects until the timeout",
+			fakeTimeElapsed: 10 * time.Second,
+			calls:           []func() error{succeed},
+			wantErrs:        []error{ErrCircuitOpen},
+			wantState:       BreakerOpen,
+		},
+		{
+			desc:            "a failed probe opens again",
+			fakeTimeElapsed: 11 * time.Second,
+			calls:           []func() error{fail, succeed},
+			wantErrs:        []error{errCallFailed, ErrCircuitOpen},
+			wantState:       BreakerOpen,
+		},
+		{
+			desc:            "successful probes close",
+			fakeTimeElapsed: 21 * time.Second,
+			calls:           []func() error{succeed, succeed},
+			wantErrs:        []error{nil, nil},
+			wantState:       BreakerClosed,
+		},
+		{
+			desc:            "closing starts counting over",
+			fakeTimeElapsed: 22 * time.Second,
+			calls:           []func() error{fail, fail, fail},
+			wantErrs:        []error{errCallFailed, errCallFailed, errCallFailed},
+			wantState:       BreakerClosed,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			setFakeNow(fakeNow.Add(tC.fakeTimeElapsed))
+
+			var gotErrs []error
+			for _, call := range tC.calls {
+				gotErrs = append(gotErrs, breaker.Execute(call))
+			}
+
+			assert.Equal(t, tC.wantErrs, gotErrs)
+			assert.Equal(t, tC.wantState, breaker.State())
+		})
+	}
+
+	assert.Equal(t, []string{
+		"closed->open",
+		"open->half-open",
+		"half-open->open",
+		"open->half-open",
+		"half-open->closed",
+	}, changes)
+}
+
+func TestCircuitBreaker_HalfOpenProbes(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	breaker := NewCircuitBreaker(1*time.Minute, 1, 1, 5*time.Second, 2)
+	require.Equal(t, errCallFailed, breaker.Execute(fail))
+	setFakeNow(fakeNow.Add(5 * time.Second))
+
+	first, err := breaker.Allow()
+	require.NoError(t, err)
+	second, err := breaker.Allow()
+	require.NoError(t, err)
+
+	_, err = breaker.Allow()
+	assert.Equal(t, ErrCircuitOpen, err, "only the probes are let through")
+
+	first.Finish(nil)
+	first.Finish(errCallFailed)
+	assert.Equal(t, BreakerHalfOpen, breaker.State(), "finishing twice does nothing")
+
+	second.Finish(nil)
+	assert.Equal(t, BreakerClosed, breaker.State())
+}
+
+func TestCircuitBreaker_RollingWindow(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	breaker := NewCircuitBreaker(1*time.Minute, 0.5, 2, 5*time.Second, 1)
+	breaker.Execute(fail)
+
+	// the failure has slid out of the window by the time the next one happens
+	setFakeNow(fakeNow.Add(2 * time.Minute))
+	breaker.Execute(fail)
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	breaker.Execute(fail)
+	assert.Equal(t, BreakerOpen, breaker.State())
+}
+
+func TestCircuitBreaker_StaleCalls(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	breaker := NewCircuitBreaker(1*time.Minute, 1, 1, 5*time.Second, 1)
+	slow, err := breaker.Allow()
+	require.NoError(t, err)
+	breaker.Execute(fail)
+	setFakeNow(fakeNow.Add(5 * time.Second))
+	require.Equal(t, BreakerHalfOpen, breaker.State())
+
+	slow.Finish(errCallFailed)
+
+	assert.Equal(t, BreakerHalfOpen, breaker.State(), "a call from before the breaker opened is ignored")
+}
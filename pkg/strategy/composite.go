@@ -0,0 +1,169 @@
+package strategy
+
+import (
+	"fmt"
+	"sync"
+)
+
+// LimitExceededError reports which limit of a CompositeLimiter rejected a request.
+type LimitExceededError struct {
+	Name   string
+	Result Result
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s limit is full", e.Name)
+}
+
+type namedLimiter struct {
+	name    string
+	limiter Limiter
+}
+
+// CompositeLimiter applies several limits together, e.g. 10 per second, 500 per minute and 20k per day. A request
+// is only recorded against the limits if every one of them allows it. The limits should not be used outside of the
+// composite, otherwise the check and the commit are no longer atomic. Limits that are not a ReservingLimiter cannot
+// be refunded, so the composite only commits to all of the limits or none if at most one of them is not.
+type CompositeLimiter struct {
+	sync.Mutex
+	limits []namedLimiter
+}
+
+func NewCompositeLimiter() *CompositeLimiter {
+	return &CompositeLimiter{}
+}
+
+// With adds a named limit. Limits are checked in the order they are added.
+func (c *CompositeLimiter) With(name string, limiter Limiter) *CompositeLimiter {
+	c.Lock()
+	defer c.Unlock()
+
+	c.limits = append(c.limits, namedLimiter{name: name, limiter: limiter})
+	return c
+}
+
+// AddN takes n from every limit if all of them allow it, otherwise nothing is taken and a *LimitExceededError
+// is returned for the first limit that rejected it.
+func (c *CompositeLimiter) AddN(n int) (bool, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, err := c.check(n); err != nil {
+		return false, err
+	}
+	if _, err := c.take(n); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Peek returns the combined result of all limits: the most restrictive remaining, and the longest retry and reset.
+func (c *CompositeLimiter) Peek(n int) Result {
+	c.Lock()
+	defer c.Unlock()
+
+	res, _ := c.check(n)
+	return res
+}
+
+func (c *CompositeLimiter) Take(n int) Result {
+	c.Lock()
+	defer c.Unlock()
+
+	res, err := c.check(n)
+	if err != nil {
+		return res
+	}
+	res, _ = c.take(n)
+	return res
+}
+
+func (c *CompositeLimiter) check(n int) (Result, error) {
+	var results []Result
+	var err error
+	for _, l := range c.limits {
+		res := l.limiter.Peek(n)
+		if !res.Allowed && err == nil {
+			err = &LimitExceededError{Name: l.name, Result: res}
+		}
+		results = append(results, res)
+	}
+	if err != nil {
+		return notTakenResult(results, n), err
+	}
+	return combineResults(results), nil
+}
+
+// take takes n from every limit. Each limit reads the clock again, so one can still reject n after check allowed
+// it, e.g. when the weighted count of a sliding window has gone up in between. What was already taken is then
+// refunded. Limits that cannot hand back a reservation are taken last, as they cannot be refunded.
+func (c *CompositeLimiter) take(n int) (Result, error) {
+	var reservations []*Reservation
+	var results []Result
+	for _, l := range c.takeOrder() {
+		var res Result
+		if r, ok := asReserving(l.limiter); ok {
+			var reservation *Reservation
+			res, reservation = r.Reserve(n)
+			if reservation != nil {
+				reservations = append(reservations, reservation)
+			}
+		} else {
+			res = l.limiter.Take(n)
+		}
+		results = append(results, res)
+
+		if !res.Allowed {
+			for _, reservation := range reservations {
+				reservation.Cancel()
+			}
+			return notTakenResult(results, n), &LimitExceededError{Name: l.name, Result: res}
+		}
+	}
+	return combineResults(results), nil
+}
+
+// takeOrder returns the limits that can be reserved followed by the ones that cannot.
+func (c *CompositeLimiter) takeOrder() []namedLimiter {
+	ordered := make([]namedLimiter, 0, len(c.limits))
+	for _, l := range c.limits {
+		if _, ok := asReserving(l.limiter); ok {
+			ordered = append(ordered, l)
+		}
+	}
+	for _, l := range c.limits {
+		if _, ok := asReserving(l.limiter); !ok {
+			ordered = append(ordered, l)
+		}
+	}
+	return ordered
+}
+
+// notTakenResult combines the results when n was rejected, so limits that would have allowed n still have it
+// remaining.
+func notTakenResult(results []Result, n int) Result {
+	for i := range results {
+		if results[i].Allowed {
+			results[i].Remaining += n
+		}
+	}
+	combined := combineResults(results)
+	combined.Allowed = false
+	return combined
+}
+
+func combineResults(results []Result) Result {
+	combined := Result{Allowed: true}
+	for i, res := range results {
+		if !res.Allowed {
+			combined.Allowed = false
+		}
+		if i == 0 || res.Remaining < combined.Remaining {
+			combined.Limit = res.Limit
+			combined.Remaining = res.Remaining
+		}
+		combined.RetryAfter = maxDuration(combined.RetryAfter, res.RetryAfter)
+		combined.ResetAfter = maxDuration(combined.ResetAfter, res.ResetAfter)
+	}
+	return combined
+}
@@ -0,0 +1,206 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompositeLimiter(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	perSecond := NewFixedWindow(1*time.Second, 0, 2)
+	perMinute := NewFixedWindow(1*time.Minute, 0, 3)
+	limiter := NewCompositeLimiter().
+		With("per-second", perSecond).
+		With("per-minute", perMinute)
+
+	testCases := []struct {
+		desc            string
+		fakeTimeElapsed time.Duration
+		addN            int
+		wantCanAdd      bool
+		wantLimit       string
+		wantSecondCount int
+		wantMinuteCount int
+	}{
+		{
+			desc:            "adding within all limits works",
+			fakeTimeElapsed: 0,
+			addN:            2,
+			wantCanAdd:      true,
+			wantSecondCount: 2,
+			wantMinuteCount: 2,
+		},
+		{
+			desc:            "adding over the per-second limit is not counted in the per-minute limit",
+			fakeTimeElapsed: 0,
+			addN:            1,
+			wantCanAdd:      false,
+			wantLimit:       "per-second",
+			wantSecondCount: 2,
+			wantMinuteCount: 2,
+		},
+		{
+			desc:            "adding in the next second works",
+			fakeTimeElapsed: 1 * time.Second,
+			addN:            1,
+			wantCanAdd:      true,
+			wantSecondCount: 1,
+			wantMinuteCount: 3,
+		},
+		{
+			desc:            "adding over the per-minute limit is not counted in the per-second limit",
+			fakeTimeElapsed: 2 * time.Second,
+			addN:            1,
+			wantCanAdd:      false,
+			wantLimit:       "per-minute",
+			wantSecondCount: 0,
+			wantMinuteCount: 3,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			setFakeNow(fakeNow.Add(tC.fakeTimeElapsed))
+
+			gotCanAdd, gotErr := limiter.AddN(tC.addN)
+
+			assert.Equal(t, tC.wantCanAdd, gotCanAdd)
+			if tC.wantLimit != "" {
+				var limitErr *LimitExceededError
+				require.ErrorAs(t, gotErr, &limitErr)
+				assert.Equal(t, tC.wantLimit, limitErr.Name)
+			} else {
+				assert.NoError(t, gotErr)
+			}
+			assert.Equal(t, tC.wantSecondCount, perSecond.Count())
+			assert.Equal(t, tC.wantMinuteCount, perMinute.Count())
+		})
+	}
+}
+
+func TestCompositeLimiter_Take(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	limiter := NewCompositeLimiter().
+		With("per-second", NewFixedWindow(1*time.Second, 0, 5)).
+		With("per-minute", NewFixedWindow(1*time.Minute, 0, 8))
+
+	t.Run("take reports the most restrictive remaining", func(t *testing.T) {
+		res := limiter.Take(4)
+
+		assert.Equal(t, Result{Allowed: true, Limit: 5, Remaining: 1, ResetAfter: 60 * time.Second}, res)
+	})
+
+	t.Run("peek reports the longest retry of the limits that rejected", func(t *testing.T) {
+		setFakeNow(fakeNow.Add(1 * time.Second))
+
+		res := limiter.Peek(5)
+
+		assert.Equal(t, Result{Allowed: false, Limit: 8, Remaining: 4, RetryAfter: 59 * time.Second, ResetAfter: 59 * time.Second}, res)
+	})
+}
+
+func TestCompositeLimiter_SyncSlidingWindows(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	perSecond := NewSyncSlidingWindow(1*time.Second, 10)
+	perDay := NewSyncSlidingWindow(24*time.Hour, 5)
+	limiter := NewCompositeLimiter().
+		With("per-second", perSecond).
+		With("per-day", perDay)
+
+	gotCanAdd, gotErr := limiter.AddN(6)
+
+	assert.False(t, gotCanAdd)
+	assert.EqualError(t, gotErr, "per-day limit is full")
+	gotPrevCount, gotCurrCount := perSecond.Count()
+	assert.Equal(t, 0, gotPrevCount)
+	assert.Equal(t, 0, gotCurrCount)
+}
+
+// takeRejectingLimiter allows every peek but rejects every take, like a limit whose count went up between the two.
+type takeRejectingLimiter struct {
+	takes int
+}
+
+func (l *takeRejectingLimiter) Peek(n int) Result {
+	return Result{Allowed: true, Limit: 10, Remaining: 10 - n}
+}
+
+func (l *takeRejectingLimiter) Take(n int) Result {
+	l.takes++
+	return Result{Allowed: false, Limit: 10, Remaining: 0, RetryAfter: 1 * time.Second}
+}
+
+func TestCompositeLimiter_TakeRejectedAfterPeek(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	perSecond := NewFixedWindow(1*time.Second, 0, 5)
+	gcra, err := NewGCRA(1, 1*time.Second, 5)
+	require.NoError(t, err)
+	rejecting := &takeRejectingLimiter{}
+	limiter := NewCompositeLimiter().
+		With("rejecting", rejecting).
+		With("per-second", perSecond).
+		With("gcra", gcra)
+
+	gotCanAdd, gotErr := limiter.AddN(2)
+
+	assert.False(t, gotCanAdd)
+	assert.EqualError(t, gotErr, "rejecting limit is full")
+	assert.Equal(t, 1, rejecting.takes)
+	assert.Equal(t, 0, perSecond.Count())
+	assert.True(t, gcra.Peek(5).Allowed)
+
+	res := limiter.Take(2)
+
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, perSecond.Count())
+	assert.True(t, gcra.Peek(5).Allowed)
+}
+
+// reserveRejectingLimiter is a takeRejectingLimiter that can be reserved, so it is taken along with the other
+// reserved limits rather than last.
+type reserveRejectingLimiter struct {
+	takeRejectingLimiter
+}
+
+func (l *reserveRejectingLimiter) Reserve(n int) (Result, *Reservation) {
+	return l.Take(n), nil
+}
+
+func TestCompositeLimiter_ObservedLimits(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		rejecting Limiter
+	}{
+		{desc: "wrapping a limiter that cannot be reserved", rejecting: &takeRejectingLimiter{}},
+		{desc: "wrapping a limiter that can be reserved", rejecting: &reserveRejectingLimiter{}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			teardown := fakeTimeSetup(t)
+			defer teardown()
+
+			var events []Event
+			observer := ObserverFunc(func(e Event) { events = append(events, e) })
+			perSecond := NewFixedWindow(1*time.Second, 0, 5)
+			limiter := NewCompositeLimiter().
+				With("per-second", NewObservedLimiter(perSecond, observer)).
+				With("rejecting", NewObservedLimiter(tC.rejecting, observer))
+
+			res := limiter.Take(2)
+
+			assert.False(t, res.Allowed)
+			assert.Equal(t, 0, perSecond.Count(), "the observed per-second limit is refunded")
+			assert.Equal(t, []EventKind{EventAllow, EventDeny}, []EventKind{events[0].Kind, events[1].Kind})
+		})
+	}
+}
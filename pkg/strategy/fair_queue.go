@@ -0,0 +1,222 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+type FairPolicy int
+
+const (
+	// DeficitRoundRobin serves the flows in turn, each up to its weight in items per round.
+	DeficitRoundRobin FairPolicy = iota
+	// WeightedFairQueuing serves the item that would finish first if every flow was served at the same time at
+	// a rate proportional to its weight.
+	WeightedFairQueuing
+)
+
+type fairItem[T any] struct {
+	item   T
+	finish float64
+}
+
+type flow[K comparable, T any] struct {
+	key     K
+	weight  int
+	cap     int
+	items   []fairItem[T]
+	deficit int
+	visited bool
+	// lastFinish is the finish tag of the last item enqueued, for WeightedFairQueuing
+	lastFinish float64
+	dropped    int
+}
+
+// FlowStats describes the queue of a flow.
+type FlowStats struct {
+	Queued  int
+	Dropped int
+}
+
+// FairQueue keeps a queue per flow and dequeues from them fairly, so that a flow that sends a lot cannot starve
+// the others. Flows have a weight of 1 and hold up to the default cap unless set with SetFlow, and items
+// enqueued to a full flow are dropped.
+type FairQueue[K comparable, T any] struct {
+	sync.Mutex
+	policy      FairPolicy
+	defaultCap  int
+	flows       map[K]*flow[K, T]
+	active      []*flow[K, T]
+	next        int
+	virtualTime float64
+	queued      int
+	notify      chan struct{}
+}
+
+func NewFairQueue[K comparable, T any](policy FairPolicy, defaultCap int) *FairQueue[K, T] {
+	return &FairQueue[K, T]{
+		policy:     policy,
+		defaultCap: defaultCap,
+		flows:      make(map[K]*flow[K, T]),
+		notify:     make(chan struct{}, 1),
+	}
+}
+
+// SetFlow sets the weight and cap of a flow. Weights below 1 are treated as 1.
+func (q *FairQueue[K, T]) SetFlow(key K, weight int, cap int) *FairQueue[K, T] {
+	q.Lock()
+	defer q.Unlock()
+
+	f := q.flow(key)
+	f.weight = max(1, weight)
+	f.cap = cap
+	return q
+}
+
+// Enqueue adds item to the flow of key, dropping it if the flow is full.
+func (q *FairQueue[K, T]) Enqueue(key K, item T) error {
+	q.Lock()
+	defer q.Unlock()
+
+	f := q.flow(key)
+	if len(f.items) >= f.cap {
+		f.dropped++
+		return errors.New("flow queue is full")
+	}
+
+	finish := math.Max(q.virtualTime, f.lastFinish) + 1/float64(f.weight)
+	f.lastFinish = finish
+	f.items = append(f.items, fairItem[T]{item: item, finish: finish})
+	if len(f.items) == 1 {
+		q.active = append(q.active, f)
+	}
+	q.queued++
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Dequeue removes the next item by the queue's policy, returning false if every flow is empty.
+func (q *FairQueue[K, T]) Dequeue() (K, T, bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	if len(q.active) == 0 {
+		var key K
+		var item T
+		return key, item, false
+	}
+
+	var i int
+	if q.policy == WeightedFairQueuing {
+		i = q.nextFinish()
+	} else {
+		i = q.nextRoundRobin()
+	}
+
+	f := q.active[i]
+	item := f.items[0]
+	f.items[0] = fairItem[T]{}
+	f.items = f.items[1:]
+	q.queued--
+	q.virtualTime = math.Max(q.virtualTime, item.finish)
+
+	if len(f.items) == 0 {
+		f.deficit = 0
+		f.visited = false
+		q.active = append(q.active[:i], q.active[i+1:]...)
+		if q.next >= len(q.active) {
+			q.next = 0
+		}
+	}
+	return f.key, item.item, true
+}
+
+// nextRoundRobin returns the index of the active flow to dequeue from, giving each flow its weight in items every
+// time its turn comes round.
+func (q *FairQueue[K, T]) nextRoundRobin() int {
+	for {
+		f := q.active[q.next]
+		if !f.visited {
+			f.deficit += f.weight
+			f.visited = true
+		}
+		if f.deficit > 0 {
+			f.deficit--
+			return q.next
+		}
+		f.visited = false
+		q.next = (q.next + 1) % len(q.active)
+	}
+}
+
+// nextFinish returns the index of the active flow whose next item has the earliest finish tag.
+func (q *FairQueue[K, T]) nextFinish() int {
+	next := 0
+	for i, f := range q.active {
+		if f.items[0].finish < q.active[next].items[0].finish {
+			next = i
+		}
+	}
+	return next
+}
+
+// Len returns how many items are queued over all flows.
+func (q *FairQueue[K, T]) Len() int {
+	q.Lock()
+	defer q.Unlock()
+
+	return q.queued
+}
+
+func (q *FairQueue[K, T]) Stats(key K) FlowStats {
+	q.Lock()
+	defer q.Unlock()
+
+	f, ok := q.flows[key]
+	if !ok {
+		return FlowStats{}
+	}
+	return FlowStats{Queued: len(f.items), Dropped: f.dropped}
+}
+
+// Feed moves items into bucket in fair order whenever it has room, until ctx is done. Items in the bucket are no
+// longer scheduled fairly, so its capacity should be small. It also checks for room every pollInterval, as the
+// bucket does not say when it has leaked.
+func (q *FairQueue[K, T]) Feed(ctx context.Context, bucket *LeakyBucketChan[T], pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for bucket.Len() < bucket.Cap() {
+			_, item, ok := q.Dequeue()
+			if !ok {
+				break
+			}
+			// only Feed adds to the bucket and it has room, so this cannot fail
+			bucket.Enqueue(item)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.notify:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (q *FairQueue[K, T]) flow(key K) *flow[K, T] {
+	f, ok := q.flows[key]
+	if !ok {
+		f = &flow[K, T]{key: key, weight: 1, cap: q.defaultCap}
+		q.flows[key] = f
+	}
+	return f
+}
@@ -0,0 +1,144 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFairQueue_Dequeue(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		policy   FairPolicy
+		weights  map[string]int
+		enqueued map[string]int
+		want     []string
+	}{
+		{
+			desc:     "round robin alternates between flows",
+			policy:   DeficitRoundRobin,
+			enqueued: map[string]int{"a": 4, "b": 2},
+			want:     []string{"a", "b", "a", "b", "a", "a"},
+		},
+		{
+			desc:     "round robin serves flows up to their weight per round",
+			policy:   DeficitRoundRobin,
+			weights:  map[string]int{"a": 2},
+			enqueued: map[string]int{"a": 4, "b": 4},
+			want:     []string{"a", "a", "b", "a", "a", "b", "b", "b"},
+		},
+		{
+			desc:     "fair queuing alternates between flows",
+			policy:   WeightedFairQueuing,
+			enqueued: map[string]int{"a": 4, "b": 2},
+			want:     []string{"a", "b", "a", "b", "a", "a"},
+		},
+		{
+			desc:     "fair queuing serves flows in proportion to their weight",
+			policy:   WeightedFairQueuing,
+			weights:  map[string]int{"a": 3},
+			enqueued: map[string]int{"a": 6, "b": 2},
+			want:     []string{"a", "a", "a", "b", "a", "a", "a", "b"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			q := NewFairQueue[string, int](tC.policy, 10)
+			for key, weight := range tC.weights {
+				q.SetFlow(key, weight, 10)
+			}
+			// enqueue in a fixed order, a before b
+			for _, key := range []string{"a", "b"} {
+				for i := 0; i < tC.enqueued[key]; i++ {
+					require.NoError(t, q.Enqueue(key, i))
+				}
+			}
+
+			var got []string
+			for {
+				key, _, ok := q.Dequeue()
+				if !ok {
+					break
+				}
+				got = append(got, key)
+			}
+			assert.Equal(t, tC.want, got)
+			assert.Equal(t, 0, q.Len())
+		})
+	}
+}
+
+func TestFairQueue_DequeueInOrderWithinFlow(t *testing.T) {
+	q := NewFairQueue[string, int](DeficitRoundRobin, 10)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, q.Enqueue("a", i))
+	}
+
+	for i := 0; i < 3; i++ {
+		key, item, ok := q.Dequeue()
+		require.True(t, ok)
+		assert.Equal(t, "a", key)
+		assert.Equal(t, i, item)
+	}
+}
+
+func TestFairQueue_Drops(t *testing.T) {
+	q := NewFairQueue[string, int](DeficitRoundRobin, 2).SetFlow("b", 1, 1)
+
+	for i := 0; i < 3; i++ {
+		err := q.Enqueue("a", i)
+		if i < 2 {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, "flow queue is full")
+		}
+	}
+	require.NoError(t, q.Enqueue("b", 0))
+	assert.EqualError(t, q.Enqueue("b", 1), "flow queue is full")
+	assert.EqualError(t, q.Enqueue("b", 2), "flow queue is full")
+
+	assert.Equal(t, FlowStats{Queued: 2, Dropped: 1}, q.Stats("a"))
+	assert.Equal(t, FlowStats{Queued: 1, Dropped: 2}, q.Stats("b"))
+	assert.Equal(t, FlowStats{}, q.Stats("c"))
+	assert.Equal(t, 3, q.Len())
+
+	// a flow that was drained has room again, and keeps its drop count
+	q.Dequeue()
+	q.Dequeue()
+	require.NoError(t, q.Enqueue("b", 3))
+	assert.Equal(t, FlowStats{Queued: 1, Dropped: 2}, q.Stats("b"))
+}
+
+func TestFairQueue_Feed(t *testing.T) {
+	out := make(chan int, 10)
+	bucket := NewLeakyBucketChan(out, 1, 100)
+	bucket.pollInterval = 10 * time.Millisecond
+	bucket.Start()
+	defer bucket.Stop()
+
+	q := NewFairQueue[string, int](DeficitRoundRobin, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Feed(ctx, bucket, 10*time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, q.Enqueue("a", i))
+		require.NoError(t, q.Enqueue("b", 10+i))
+	}
+
+	var got []int
+	timeout := time.After(2 * time.Second)
+	for len(got) < 6 {
+		select {
+		case item := <-out:
+			got = append(got, item)
+		case <-timeout:
+			t.Fatalf("only received %v", got)
+		}
+	}
+	assert.ElementsMatch(t, []int{0, 1, 2, 10, 11, 12}, got)
+	assert.Equal(t, 0, q.Len())
+}
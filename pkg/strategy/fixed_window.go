@@ -0,0 +1,128 @@
+package strategy
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// FixedWindow counts requests in consecutive windows of a fixed interval that are aligned to wall-clock
+// boundaries, e.g. an interval of 1 minute starts a new window at the top of every minute. The offset shifts the
+// boundaries, so an interval of 1 minute with an offset of 30 seconds starts new windows at hh:mm:30.
+type FixedWindow struct {
+	sync.Mutex
+	curr     *window
+	interval time.Duration
+	offset   time.Duration
+	capacity int
+}
+
+func NewFixedWindow(interval time.Duration, offset time.Duration, capacity int) *FixedWindow {
+	w := &FixedWindow{
+		interval: interval,
+		offset:   offset,
+		capacity: capacity,
+	}
+	w.curr = newWindow(w.windowStart(now()))
+	return w
+}
+
+func (w *FixedWindow) Count() int {
+	w.Lock()
+	defer w.Unlock()
+
+	w.adjustWindow(now())
+	return w.curr.Count()
+}
+
+func (w *FixedWindow) AddN(n int) (bool, error) {
+	if !w.Take(n).Allowed {
+		return false, errors.New("fixed window is full")
+	}
+	return true, nil
+}
+
+func (w *FixedWindow) Peek(n int) Result {
+	w.Lock()
+	defer w.Unlock()
+
+	return w.check(now(), n)
+}
+
+func (w *FixedWindow) Take(n int) Result {
+	w.Lock()
+	defer w.Unlock()
+
+	res := w.check(now(), n)
+	if res.Allowed {
+		w.curr.AddN(n)
+	}
+	return res
+}
+
+// ReserveN takes n like AddN and returns a reservation that refunds to the window n was taken in. Once that
+// window has ended there is nothing to refund.
+func (w *FixedWindow) ReserveN(n int) (*Reservation, bool) {
+	_, r := w.Reserve(n)
+	return r, r != nil
+}
+
+func (w *FixedWindow) Reserve(n int) (Result, *Reservation) {
+	w.Lock()
+	defer w.Unlock()
+
+	res := w.check(now(), n)
+	if !res.Allowed {
+		return res, nil
+	}
+	w.curr.AddN(n)
+
+	startTime := w.curr.StartTime()
+	return res, newReservation(n, func(n int) {
+		w.Lock()
+		defer w.Unlock()
+
+		if w.curr.StartTime().Equal(startTime) {
+			w.curr.SetCount(max(0, w.curr.Count()-n))
+		}
+	})
+}
+
+// ResetTime returns the time at which the current window ends and the count goes back to 0.
+func (w *FixedWindow) ResetTime() time.Time {
+	w.Lock()
+	defer w.Unlock()
+
+	w.adjustWindow(now())
+	return w.curr.StartTime().Add(w.interval)
+}
+
+// RetryAfter returns how long a rejected caller has to wait until the window resets.
+func (w *FixedWindow) RetryAfter() time.Duration {
+	return w.ResetTime().Sub(now())
+}
+
+// windowStart truncates t down to the closest window boundary, taking the offset into account.
+func (w *FixedWindow) windowStart(t time.Time) time.Time {
+	return t.Add(-w.offset).Truncate(w.interval).Add(w.offset)
+}
+
+func (w *FixedWindow) check(t time.Time, n int) Result {
+	w.adjustWindow(t)
+
+	res := countResult(w.curr.Count(), w.capacity, n)
+	resetAfter := w.curr.StartTime().Add(w.interval).Sub(t)
+	if !res.Allowed {
+		res.RetryAfter = resetAfter
+	}
+	if w.curr.Count()+allowedN(res, n) > 0 {
+		res.ResetAfter = resetAfter
+	}
+	return res
+}
+
+func (w *FixedWindow) adjustWindow(t time.Time) {
+	if start := w.windowStart(t); !start.Equal(w.curr.StartTime()) {
+		w.curr.Set(start, 0)
+	}
+}
@@ -0,0 +1,119 @@
+package strategy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixedWindow(t *testing.T) {
+	capacity := 10
+	interval := 1 * time.Minute
+	testCases := []struct {
+		desc           string
+		offset         time.Duration
+		startTime      time.Time
+		firstAddN      int
+		secondAddN     int
+		secondAddTime  time.Time
+		wantCanAdd     bool
+		wantErr        error
+		wantCount      int
+		wantResetTime  time.Time
+		wantRetryAfter time.Duration
+	}{
+		{
+			desc:           "adding twice in the same window works",
+			startTime:      fakeNow.Add(10 * time.Second),
+			firstAddN:      5,
+			secondAddN:     5,
+			secondAddTime:  fakeNow.Add(59 * time.Second),
+			wantCanAdd:     true,
+			wantCount:      10,
+			wantResetTime:  fakeNow.Add(1 * time.Minute),
+			wantRetryAfter: 1 * time.Second,
+		},
+		{
+			desc:           "over capacity in the same window returns error",
+			startTime:      fakeNow.Add(10 * time.Second),
+			firstAddN:      5,
+			secondAddN:     6,
+			secondAddTime:  fakeNow.Add(50 * time.Second),
+			wantCanAdd:     false,
+			wantErr:        errors.New("fixed window is full"),
+			wantCount:      5,
+			wantResetTime:  fakeNow.Add(1 * time.Minute),
+			wantRetryAfter: 10 * time.Second,
+		},
+		{
+			desc:           "window resets at the top of the minute rather than a minute after the first add",
+			startTime:      fakeNow.Add(50 * time.Second),
+			firstAddN:      10,
+			secondAddN:     10,
+			secondAddTime:  fakeNow.Add(1 * time.Minute),
+			wantCanAdd:     true,
+			wantCount:      10,
+			wantResetTime:  fakeNow.Add(2 * time.Minute),
+			wantRetryAfter: 1 * time.Minute,
+		},
+		{
+			desc:           "window resets after several idle windows",
+			startTime:      fakeNow,
+			firstAddN:      10,
+			secondAddN:     3,
+			secondAddTime:  fakeNow.Add(5*time.Minute + 30*time.Second),
+			wantCanAdd:     true,
+			wantCount:      3,
+			wantResetTime:  fakeNow.Add(6 * time.Minute),
+			wantRetryAfter: 30 * time.Second,
+		},
+		{
+			desc:           "offset moves the window boundary",
+			offset:         30 * time.Second,
+			startTime:      fakeNow.Add(10 * time.Second),
+			firstAddN:      10,
+			secondAddN:     1,
+			secondAddTime:  fakeNow.Add(20 * time.Second),
+			wantCanAdd:     false,
+			wantErr:        errors.New("fixed window is full"),
+			wantCount:      10,
+			wantResetTime:  fakeNow.Add(30 * time.Second),
+			wantRetryAfter: 10 * time.Second,
+		},
+		{
+			desc:           "offset window resets at the offset boundary",
+			offset:         30 * time.Second,
+			startTime:      fakeNow.Add(10 * time.Second),
+			firstAddN:      10,
+			secondAddN:     10,
+			secondAddTime:  fakeNow.Add(30 * time.Second),
+			wantCanAdd:     true,
+			wantCount:      10,
+			wantResetTime:  fakeNow.Add(90 * time.Second),
+			wantRetryAfter: 1 * time.Minute,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			teardown := fakeTimeSetup(t)
+			defer teardown()
+
+			setFakeNow(tC.startTime)
+			fw := NewFixedWindow(interval, tC.offset, capacity)
+			_, gotErr := fw.AddN(tC.firstAddN)
+			require.NoError(t, gotErr)
+
+			setFakeNow(tC.secondAddTime)
+
+			gotCanAdd, gotErr := fw.AddN(tC.secondAddN)
+			assert.Equal(t, tC.wantCanAdd, gotCanAdd)
+			assert.Equal(t, tC.wantErr, gotErr)
+			assert.Equal(t, tC.wantCount, fw.Count())
+			assert.Equal(t, tC.wantResetTime, fw.ResetTime())
+			assert.Equal(t, tC.wantRetryAfter, fw.RetryAfter())
+		})
+	}
+}
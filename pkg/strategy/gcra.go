@@ -0,0 +1,227 @@
+package strategy
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// gcra holds the parameters of the generic cell rate algorithm. Rather than counting, the algorithm keeps a single
+// theoretical arrival time (TAT) which is pushed forward by the emission interval for every cell that is admitted.
+// A request is admitted as long as the TAT does not move more than the burst tolerance ahead of the current time.
+type gcra struct {
+	emissionInterval time.Duration
+	tolerance        time.Duration
+}
+
+func newGCRA(rate int, interval time.Duration, burst int) (gcra, error) {
+	if rate <= 0 {
+		return gcra{}, errors.New("rate must be greater than 0")
+	}
+	if interval <= 0 {
+		return gcra{}, errors.New("interval must be greater than 0")
+	}
+	// burst is how many cells are admitted at once, so with less than 1 nothing would ever be admitted
+	if burst < 1 {
+		return gcra{}, errors.New("burst must be at least 1")
+	}
+	emissionInterval := interval / time.Duration(rate)
+	if emissionInterval == 0 {
+		return gcra{}, errors.New("rate must not be more than 1 per nanosecond of the interval")
+	}
+	return gcra{
+		emissionInterval: emissionInterval,
+		tolerance:        emissionInterval * time.Duration(burst),
+	}, nil
+}
+
+// allowN returns the new TAT if n cells are admitted at t, otherwise how many cells it would have gone over.
+func (g gcra) allowN(tat time.Time, t time.Time, n int) (time.Time, bool, int) {
+	if tat.Before(t) {
+		tat = t
+	}
+	newTat := tat.Add(g.emissionInterval * time.Duration(n))
+	over := newTat.Sub(t) - g.tolerance
+	if over > 0 {
+		spillover := int((over + g.emissionInterval - 1) / g.emissionInterval)
+		return tat, false, spillover
+	}
+	return newTat, true, 0
+}
+
+// retryAfter returns how long to wait from t until n cells can be admitted.
+func (g gcra) retryAfter(tat time.Time, t time.Time, n int) time.Duration {
+	if tat.Before(t) {
+		tat = t
+	}
+	allowAt := tat.Add(g.emissionInterval*time.Duration(n) - g.tolerance)
+	return maxDuration(0, allowAt.Sub(t))
+}
+
+// check returns the result of admitting n cells at t along with the new TAT.
+func (g gcra) check(tat time.Time, t time.Time, n int) (Result, time.Time) {
+	newTat, success, _ := g.allowN(tat, t, n)
+	res := Result{
+		Allowed:   success,
+		Limit:     int(g.tolerance / g.emissionInterval),
+		Remaining: int((g.tolerance - maxDuration(0, newTat.Sub(t))) / g.emissionInterval),
+	}
+	if !success {
+		res.RetryAfter = g.retryAfter(tat, t, n)
+	}
+	res.ResetAfter = maxDuration(0, newTat.Sub(t))
+	return res, newTat
+}
+
+// GCRA limits to rate cells per interval with bursts of up to burst cells, storing only a single timestamp.
+type GCRA struct {
+	sync.Mutex
+	gcra
+	tat time.Time
+}
+
+// NewGCRA returns an error if rate or interval is not positive, burst is less than 1, or rate is so high that the
+// emission interval rounds down to 0.
+func NewGCRA(rate int, interval time.Duration, burst int) (*GCRA, error) {
+	g, err := newGCRA(rate, interval, burst)
+	if err != nil {
+		return nil, err
+	}
+	return &GCRA{
+		gcra: g,
+		tat:  now(),
+	}, nil
+}
+
+// AllowN works like LeakyBucket.AddN, returning whether n was admitted and if not, how many it went over the limit.
+// Unlike LeakyBucket, a rejected request is not recorded.
+func (g *GCRA) AllowN(n int) (bool, int) {
+	g.Lock()
+	defer g.Unlock()
+
+	newTat, success, spillover := g.allowN(g.tat, now(), n)
+	g.tat = newTat
+	return success, spillover
+}
+
+func (g *GCRA) Peek(n int) Result {
+	g.Lock()
+	defer g.Unlock()
+
+	res, _ := g.check(g.tat, now(), n)
+	return res
+}
+
+func (g *GCRA) Take(n int) Result {
+	g.Lock()
+	defer g.Unlock()
+
+	res, newTat := g.check(g.tat, now(), n)
+	g.tat = newTat
+	return res
+}
+
+// ReserveN takes n like Take and returns a reservation that moves the TAT back by the cells it refunds.
+func (g *GCRA) ReserveN(n int) (*Reservation, bool) {
+	_, r := g.Reserve(n)
+	return r, r != nil
+}
+
+func (g *GCRA) Reserve(n int) (Result, *Reservation) {
+	g.Lock()
+	defer g.Unlock()
+
+	res, newTat := g.check(g.tat, now(), n)
+	g.tat = newTat
+	if !res.Allowed {
+		return res, nil
+	}
+	return res, newReservation(n, func(n int) {
+		g.Lock()
+		defer g.Unlock()
+
+		g.tat = g.tat.Add(-g.emissionInterval * time.Duration(n))
+	})
+}
+
+// RetryAfter returns how long to wait until n can be admitted.
+func (g *GCRA) RetryAfter(n int) time.Duration {
+	g.Lock()
+	defer g.Unlock()
+
+	return g.retryAfter(g.tat, now(), n)
+}
+
+// KeyedGCRA applies the same GCRA limit to every key independently. Only the TAT is stored per key, and keys
+// whose TAT has passed are indistinguishable from unseen keys, so they can be dropped with Prune.
+type KeyedGCRA[K comparable] struct {
+	sync.Mutex
+	gcra
+	tats map[K]int64
+}
+
+// NewKeyedGCRA returns an error for the same parameters as NewGCRA.
+func NewKeyedGCRA[K comparable](rate int, interval time.Duration, burst int) (*KeyedGCRA[K], error) {
+	g, err := newGCRA(rate, interval, burst)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyedGCRA[K]{
+		gcra: g,
+		tats: make(map[K]int64),
+	}, nil
+}
+
+func (g *KeyedGCRA[K]) AllowN(key K, n int) (bool, int) {
+	g.Lock()
+	defer g.Unlock()
+
+	newTat, success, spillover := g.allowN(g.tat(key), now(), n)
+	g.tats[key] = newTat.UnixNano()
+	return success, spillover
+}
+
+func (g *KeyedGCRA[K]) RetryAfter(key K, n int) time.Duration {
+	g.Lock()
+	defer g.Unlock()
+
+	return g.retryAfter(g.tat(key), now(), n)
+}
+
+func (g *KeyedGCRA[K]) Len() int {
+	g.Lock()
+	defer g.Unlock()
+
+	return len(g.tats)
+}
+
+// Prune removes all keys whose TAT has passed and returns how many were removed.
+func (g *KeyedGCRA[K]) Prune() int {
+	g.Lock()
+	defer g.Unlock()
+
+	tNow := now().UnixNano()
+	pruned := 0
+	for key, tat := range g.tats {
+		if tat <= tNow {
+			delete(g.tats, key)
+			pruned++
+		}
+	}
+	return pruned
+}
+
+func (g *KeyedGCRA[K]) tat(key K) time.Time {
+	tat, ok := g.tats[key]
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(0, tat)
+}
+
+func maxDuration(x, y time.Duration) time.Duration {
+	if x > y {
+		return x
+	}
+	return y
+}
@@ -0,0 +1,175 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCRA(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		initialAllowN   int
+		fakeTimeElapsed time.Duration
+		afterTimeAllowN int
+		wantSuccess     bool
+		wantSpillover   int
+		wantRetryAfter  time.Duration
+	}{
+		{
+			desc:            "when allowing within burst, should succeed without spillover",
+			initialAllowN:   2,
+			fakeTimeElapsed: 0,
+			afterTimeAllowN: 3,
+			wantSuccess:     true,
+			wantSpillover:   0,
+			wantRetryAfter:  300 * time.Millisecond,
+		},
+		{
+			desc:            "when allowing just over burst, should fail with 1 spillover",
+			initialAllowN:   5,
+			fakeTimeElapsed: 0,
+			afterTimeAllowN: 1,
+			wantSuccess:     false,
+			wantSpillover:   1,
+			wantRetryAfter:  100 * time.Millisecond,
+		},
+		{
+			desc:            "when allowing after the emission interval, should succeed",
+			initialAllowN:   5,
+			fakeTimeElapsed: 100 * time.Millisecond,
+			afterTimeAllowN: 1,
+			wantSuccess:     true,
+			wantSpillover:   0,
+			wantRetryAfter:  100 * time.Millisecond,
+		},
+		{
+			desc:            "when partially recovered, spillover rounds up to whole cells",
+			initialAllowN:   5,
+			fakeTimeElapsed: 250 * time.Millisecond,
+			afterTimeAllowN: 3,
+			wantSuccess:     false,
+			wantSpillover:   1,
+			wantRetryAfter:  50 * time.Millisecond,
+		},
+		{
+			desc:            "when allowing more than the burst at once, should fail with the difference",
+			initialAllowN:   0,
+			fakeTimeElapsed: 0,
+			afterTimeAllowN: 8,
+			wantSuccess:     false,
+			wantSpillover:   3,
+			wantRetryAfter:  300 * time.Millisecond,
+		},
+		{
+			desc:            "when idle for longer than the burst tolerance, should allow the full burst",
+			initialAllowN:   5,
+			fakeTimeElapsed: 10 * time.Second,
+			afterTimeAllowN: 5,
+			wantSuccess:     true,
+			wantSpillover:   0,
+			wantRetryAfter:  500 * time.Millisecond,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			teardown := fakeTimeSetup(t)
+			defer teardown()
+
+			limiter, err := NewGCRA(10, 1*time.Second, 5)
+			require.NoError(t, err)
+			success, _ := limiter.AllowN(tC.initialAllowN)
+			require.True(t, success)
+			setFakeNow(fakeNow.Add(tC.fakeTimeElapsed))
+
+			gotSuccess, gotSpillover := limiter.AllowN(tC.afterTimeAllowN)
+
+			assert.Equal(t, tC.wantSuccess, gotSuccess)
+			assert.Equal(t, tC.wantSpillover, gotSpillover)
+			assert.Equal(t, tC.wantRetryAfter, limiter.RetryAfter(tC.afterTimeAllowN))
+		})
+	}
+}
+
+func TestGCRA_BurstOfOne(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	limiter, err := NewGCRA(10, 1*time.Second, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, Result{Allowed: true, Limit: 1, Remaining: 0, ResetAfter: 100 * time.Millisecond}, limiter.Peek(1))
+
+	success, _ := limiter.AllowN(1)
+	assert.True(t, success)
+	success, spillover := limiter.AllowN(1)
+	assert.False(t, success)
+	assert.Equal(t, 1, spillover)
+	assert.Equal(t, 100*time.Millisecond, limiter.RetryAfter(1))
+
+	setFakeNow(fakeNow.Add(100 * time.Millisecond))
+	success, _ = limiter.AllowN(1)
+	assert.True(t, success)
+}
+
+func TestKeyedGCRA(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	limiter, err := NewKeyedGCRA[string](1, 1*time.Second, 2)
+	require.NoError(t, err)
+
+	t.Run("keys are limited independently", func(t *testing.T) {
+		success, _ := limiter.AllowN("a", 2)
+		assert.True(t, success)
+
+		success, spillover := limiter.AllowN("a", 1)
+		assert.False(t, success)
+		assert.Equal(t, 1, spillover)
+		assert.Equal(t, 1*time.Second, limiter.RetryAfter("a", 1))
+
+		success, _ = limiter.AllowN("b", 1)
+		assert.True(t, success)
+		assert.Equal(t, time.Duration(0), limiter.RetryAfter("b", 1))
+		assert.Equal(t, 2, limiter.Len())
+	})
+
+	t.Run("prune only removes keys that have fully recovered", func(t *testing.T) {
+		setFakeNow(fakeNow.Add(1 * time.Second))
+
+		assert.Equal(t, 1, limiter.Prune())
+		assert.Equal(t, 1, limiter.Len())
+
+		setFakeNow(fakeNow.Add(2 * time.Second))
+
+		assert.Equal(t, 1, limiter.Prune())
+		assert.Equal(t, 0, limiter.Len())
+	})
+}
+
+func TestNewGCRA_InvalidParameters(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rate     int
+		interval time.Duration
+		burst    int
+	}{
+		{desc: "zero rate", rate: 0, interval: 1 * time.Second, burst: 1},
+		{desc: "negative rate", rate: -1, interval: 1 * time.Second, burst: 1},
+		{desc: "zero interval", rate: 1, interval: 0, burst: 1},
+		{desc: "zero burst", rate: 1, interval: 1 * time.Second, burst: 0},
+		{desc: "negative burst", rate: 1, interval: 1 * time.Second, burst: -1},
+		{desc: "rate higher than the interval in nanoseconds", rate: 11, interval: 10 * time.Nanosecond, burst: 1},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			_, err := NewGCRA(tC.rate, tC.interval, tC.burst)
+			assert.Error(t, err)
+
+			_, err = NewKeyedGCRA[string](tC.rate, tC.interval, tC.burst)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+)
+
+type htbNode struct {
+	name     string
+	parent   *htbNode
+	children []*htbNode
+	bucket   *datastruct.TokenBucket
+	// ceiling caps the tokens taken through the node including those borrowed, nil if it cannot borrow
+	ceiling *datastruct.TokenBucket
+}
+
+// HierarchicalLimiter is a tree of token buckets, like Linux HTB: a global budget at the root with sub-budgets
+// below it, e.g. per tenant. A take from a node is charged to the node and every ancestor, and only succeeds if
+// all of them allow it.
+//
+// A node that has run out of its own tokens can borrow its parent's, up to its ceiling. It can only borrow what
+// its parent has beyond the tokens its siblings still have, so that a node that borrows does not starve its
+// siblings of their own budgets. For that to hold, the rate and burst of a parent should be at least the sum of
+// its children's.
+type HierarchicalLimiter struct {
+	sync.Mutex
+	root  *htbNode
+	nodes map[string]*htbNode
+}
+
+// NewHierarchicalLimiter creates the tree with its root node, holding up to burst tokens refilled at rate per
+// second.
+func NewHierarchicalLimiter(root string, rate float64, burst float64) *HierarchicalLimiter {
+	node := &htbNode{
+		name:   root,
+		bucket: datastruct.NewTokenBucket(burst, rate),
+	}
+	return &HierarchicalLimiter{
+		root:  node,
+		nodes: map[string]*htbNode{root: node},
+	}
+}
+
+// Add adds a node under parent with its own budget of burst tokens refilled at rate per second. ceilBurst and
+// ceilRate cap the node's takes including what it borrows, and a ceilBurst no higher than burst means the node
+// never borrows.
+func (h *HierarchicalLimiter) Add(name string, parent string, rate float64, burst float64, ceilRate float64, ceilBurst float64) error {
+	h.Lock()
+	defer h.Unlock()
+
+	if _, ok := h.nodes[name]; ok {
+		return fmt.Errorf("node %q already exists", name)
+	}
+	p, ok := h.nodes[parent]
+	if !ok {
+		return fmt.Errorf("parent node %q does not exist", parent)
+	}
+
+	node := &htbNode{
+		name:   name,
+		parent: p,
+		bucket: datastruct.NewTokenBucket(burst, rate),
+	}
+	if ceilBurst > burst {
+		node.ceiling = datastruct.NewTokenBucket(ceilBurst, ceilRate)
+	}
+	p.children = append(p.children, node)
+	h.nodes[name] = node
+	return nil
+}
+
+func (h *HierarchicalLimiter) Peek(name string, n int) (Result, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	node, ok := h.nodes[name]
+	if !ok {
+		return Result{}, errors.New("unknown node")
+	}
+	res, _ := h.check(node, n)
+	return res, nil
+}
+
+// Take takes n from the node called name and all of its ancestors, or from none of them if any does not allow it.
+func (h *HierarchicalLimiter) Take(name string, n int) (Result, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	node, ok := h.nodes[name]
+	if !ok {
+		return Result{}, errors.New("unknown node")
+	}
+
+	res, borrowing := h.check(node, n)
+	if !res.Allowed {
+		return res, nil
+	}
+	for i, x := 0, node; x != nil; i, x = i+1, x.parent {
+		// a node that borrows is charged through its parent instead, as it does not have the tokens itself
+		if !borrowing[i] {
+			x.bucket.TakeN(n)
+		}
+		if x.ceiling != nil {
+			x.ceiling.TakeN(n)
+		}
+	}
+	return res, nil
+}
+
+// Tokens returns the tokens of the node's own budget.
+func (h *HierarchicalLimiter) Tokens(name string) float64 {
+	h.Lock()
+	defer h.Unlock()
+
+	node, ok := h.nodes[name]
+	if !ok {
+		return 0
+	}
+	return node.bucket.Tokens()
+}
+
+// check returns the result of taking n from node, and whether each node from it to the root would borrow.
+func (h *HierarchicalLimiter) check(node *htbNode, n int) (Result, []bool) {
+	need := float64(n)
+	res := Result{
+		Allowed:   true,
+		Limit:     int(node.bucket.MaxTokens()),
+		Remaining: int(node.bucket.Tokens()),
+	}
+
+	var borrowing []bool
+	for x := node; x != nil; x = x.parent {
+		tokens := x.bucket.Tokens()
+		borrows := tokens < need
+		borrowing = append(borrowing, borrows)
+
+		allowed := !borrows
+		if borrows && x.ceiling != nil && x.ceiling.Tokens() >= need {
+			allowed = x.parent.bucket.Tokens()-need >= x.parent.reserved(x)
+		}
+		if x.ceiling != nil && x.ceiling.Tokens() < need {
+			allowed = false
+		}
+
+		if !allowed {
+			res.Allowed = false
+			res.RetryAfter = maxDuration(res.RetryAfter, x.refillDuration(need-tokens))
+		}
+	}
+	if res.Allowed && !borrowing[0] {
+		res.Remaining = int(node.bucket.Tokens() - need)
+	}
+	return res, borrowing
+}
+
+// reserved returns the tokens the other children of x still have, which x must not borrow.
+func (x *htbNode) reserved(except *htbNode) float64 {
+	reserved := 0.0
+	for _, c := range x.children {
+		if c != except {
+			reserved += c.bucket.Tokens()
+		}
+	}
+	return reserved
+}
+
+// refillDuration returns how long the node's own budget takes to refill n tokens.
+func (x *htbNode) refillDuration(n float64) time.Duration {
+	rate := x.bucket.RefillRatePerSecond()
+	if n <= 0 || rate == 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(n / rate * float64(time.Second)))
+}
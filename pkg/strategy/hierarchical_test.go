@@ -0,0 +1,160 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTenantTree(t *testing.T) *HierarchicalLimiter {
+	t.Helper()
+
+	h := NewHierarchicalLimiter("global", 0, 10)
+	require.NoError(t, h.Add("a", "global", 0, 4, 0, 10))
+	require.NoError(t, h.Add("b", "global", 0, 4, 0, 10))
+	require.NoError(t, h.Add("c", "global", 0, 2, 0, 0))
+	return h
+}
+
+func TestHierarchicalLimiter_Add(t *testing.T) {
+	h := newTenantTree(t)
+
+	assert.EqualError(t, h.Add("a", "global", 1, 1, 0, 0), `node "a" already exists`)
+	assert.EqualError(t, h.Add("d", "missing", 1, 1, 0, 0), `parent node "missing" does not exist`)
+	_, err := h.Take("missing", 1)
+	assert.EqualError(t, err, "unknown node")
+}
+
+func TestHierarchicalLimiter_Borrowing(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	h := newTenantTree(t)
+
+	testCases := []struct {
+		desc          string
+		node          string
+		n             int
+		wantAllowed   bool
+		wantRemaining int
+		wantTokens    map[string]float64
+	}{
+		{
+			desc:          "a takes its own budget, charging the global budget",
+			node:          "a",
+			n:             4,
+			wantAllowed:   true,
+			wantRemaining: 0,
+			wantTokens:    map[string]float64{"global": 6, "a": 0, "b": 4, "c": 2},
+		},
+		{
+			desc:          "a cannot borrow the tokens its siblings still have",
+			node:          "a",
+			n:             1,
+			wantAllowed:   false,
+			wantRemaining: 0,
+			wantTokens:    map[string]float64{"global": 6, "a": 0, "b": 4, "c": 2},
+		},
+		{
+			desc:          "c cannot borrow without a ceiling",
+			node:          "c",
+			n:             3,
+			wantAllowed:   false,
+			wantRemaining: 2,
+			wantTokens:    map[string]float64{"global": 6, "a": 0, "b": 4, "c": 2},
+		},
+		{
+			desc:          "c takes its own budget",
+			node:          "c",
+			n:             2,
+			wantAllowed:   true,
+			wantRemaining: 0,
+			wantTokens:    map[string]float64{"global": 4, "a": 0, "b": 4, "c": 0},
+		},
+		{
+			desc:          "b is not starved by a having borrowed",
+			node:          "b",
+			n:             4,
+			wantAllowed:   true,
+			wantRemaining: 0,
+			wantTokens:    map[string]float64{"global": 0, "a": 0, "b": 0, "c": 0},
+		},
+		{
+			desc:          "nothing is left to borrow from the global budget",
+			node:          "a",
+			n:             1,
+			wantAllowed:   false,
+			wantRemaining: 0,
+			wantTokens:    map[string]float64{"global": 0, "a": 0, "b": 0, "c": 0},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			res, err := h.Take(tC.node, tC.n)
+
+			require.NoError(t, err)
+			assert.Equal(t, tC.wantAllowed, res.Allowed)
+			assert.Equal(t, tC.wantRemaining, res.Remaining)
+			for name, want := range tC.wantTokens {
+				assert.Equal(t, want, h.Tokens(name), name)
+			}
+		})
+	}
+}
+
+func TestHierarchicalLimiter_BorrowsSpareCapacity(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	h := NewHierarchicalLimiter("global", 0, 10)
+	require.NoError(t, h.Add("a", "global", 0, 4, 0, 6))
+	require.NoError(t, h.Add("b", "global", 0, 4, 0, 4))
+
+	t.Run("a borrows the spare tokens up to its ceiling", func(t *testing.T) {
+		res, err := h.Take("a", 4)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+
+		res, err = h.Take("a", 2)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 4.0, h.Tokens("global"))
+	})
+
+	t.Run("a cannot go over its ceiling", func(t *testing.T) {
+		res, err := h.Peek("a", 0)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+
+		res, err = h.Take("a", 1)
+		require.NoError(t, err)
+		assert.False(t, res.Allowed)
+	})
+
+	t.Run("b still has its whole budget", func(t *testing.T) {
+		res, err := h.Take("b", 4)
+
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+	})
+}
+
+func TestHierarchicalLimiter_Atomic(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	h := NewHierarchicalLimiter("global", 1, 3)
+	require.NoError(t, h.Add("tenant", "global", 1, 5, 0, 0))
+	require.NoError(t, h.Add("user", "tenant", 1, 5, 0, 0))
+
+	res, err := h.Take("user", 4)
+	require.NoError(t, err)
+
+	assert.False(t, res.Allowed, "the global budget does not allow it")
+	assert.Equal(t, 1*time.Second, res.RetryAfter)
+	assert.Equal(t, 5.0, h.Tokens("user"), "nothing is taken from the nodes that allowed it")
+	assert.Equal(t, 5.0, h.Tokens("tenant"))
+	assert.Equal(t, 3.0, h.Tokens("global"))
+}
@@ -0,0 +1,263 @@
+package strategy
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type keyedEntry struct {
+	limiter  Limiter
+	lastUsed time.Time
+}
+
+// KeyedLimiter keeps a separate limiter per key, created on first use with newLimiter. Keys that have not been
+// used for longer than idleTimeout are evicted, so their limit starts over the next time they are used.
+// The limiters are only used while the KeyedLimiter is locked, so newLimiter can return ones that are not safe for
+// concurrent use.
+type KeyedLimiter[K comparable] struct {
+	sync.Mutex
+	newLimiter  func() Limiter
+	idleTimeout time.Duration
+	lastEvicted time.Time
+	entries     map[K]*keyedEntry
+	observer    Observer
+}
+
+func NewKeyedLimiter[K comparable](newLimiter func() Limiter, idleTimeout time.Duration) *KeyedLimiter[K] {
+	return &KeyedLimiter[K]{
+		newLimiter:  newLimiter,
+		idleTimeout: idleTimeout,
+		lastEvicted: now(),
+		entries:     make(map[K]*keyedEntry),
+	}
+}
+
+// WithObserver calls observer for every take, with the key formatted with fmt.Sprint, and for every evicted key.
+func (k *KeyedLimiter[K]) WithObserver(observer Observer) *KeyedLimiter[K] {
+	k.Lock()
+	defer k.Unlock()
+
+	k.observer = observer
+	return k
+}
+
+func (k *KeyedLimiter[K]) Peek(key K, n int) Result {
+	k.Lock()
+	defer k.Unlock()
+
+	return k.entry(key).limiter.Peek(n)
+}
+
+func (k *KeyedLimiter[K]) Take(key K, n int) Result {
+	k.Lock()
+	defer k.Unlock()
+
+	res := k.entry(key).limiter.Take(n)
+	if k.observer != nil {
+		k.observer.Observe(resultEvent(fmt.Sprint(key), n, res))
+	}
+	return res
+}
+
+// Reserve takes n for key like Take, returning a reservation if the limiter of key is a ReservingLimiter and
+// allowed n, otherwise nil.
+func (k *KeyedLimiter[K]) Reserve(key K, n int) (Result, *Reservation) {
+	k.Lock()
+	defer k.Unlock()
+
+	limiter := k.entry(key).limiter
+	var res Result
+	var reservation *Reservation
+	if r, ok := asReserving(limiter); ok {
+		res, reservation = r.Reserve(n)
+	} else {
+		res = limiter.Take(n)
+	}
+	if k.observer != nil {
+		k.observer.Observe(resultEvent(fmt.Sprint(key), n, res))
+	}
+	if reservation == nil {
+		return res, nil
+	}
+	return res, newReservation(n, func(n int) {
+		k.Lock()
+		defer k.Unlock()
+
+		reservation.Refund(n)
+	})
+}
+
+func (k *KeyedLimiter[K]) Len() int {
+	k.Lock()
+	defer k.Unlock()
+
+	return len(k.entries)
+}
+
+// Evict removes the keys that have been idle for longer than the idle timeout and returns how many were removed.
+// It is also done while taking, at most once per idle timeout.
+func (k *KeyedLimiter[K]) Evict() int {
+	k.Lock()
+	defer k.Unlock()
+
+	return k.evict(now())
+}
+
+func (k *KeyedLimiter[K]) evict(t time.Time) int {
+	k.lastEvicted = t
+	evicted := 0
+	for key, e := range k.entries {
+		if t.Sub(e.lastUsed) > k.idleTimeout {
+			delete(k.entries, key)
+			stopLimiter(e.limiter)
+			evicted++
+			if k.observer != nil {
+				k.observer.Observe(Event{Kind: EventEvict, Key: fmt.Sprint(key)})
+			}
+		}
+	}
+	return evicted
+}
+
+func (k *KeyedLimiter[K]) entry(key K) *keyedEntry {
+	tNow := now()
+	if tNow.Sub(k.lastEvicted) > k.idleTimeout {
+		k.evict(tNow)
+	}
+
+	e, ok := k.entries[key]
+	if !ok {
+		e = &keyedEntry{limiter: k.newLimiter()}
+		k.entries[key] = e
+	}
+	e.lastUsed = tNow
+	return e
+}
+
+// stopLimiter stops limiters that do work in the background, such as SlidingWindow, once they are dropped.
+func stopLimiter(l Limiter) {
+	if s, ok := l.(interface{ Stop() }); ok {
+		s.Stop()
+	}
+}
+
+type keyedSnapshot[K comparable] struct {
+	Key      K               `json:"key"`
+	LastUsed time.Time       `json:"lastUsed"`
+	State    json.RawMessage `json:"state"`
+}
+
+// MarshalBinary encodes every key with the state of its limiter, which must implement encoding.BinaryMarshaler.
+// Restoring replaces all keys, creating their limiters with newLimiter before restoring their state. Keys keep
+// when they were last used, so keys that went idle while the limiter was not in use are still evicted.
+func (k *KeyedLimiter[K]) MarshalBinary() ([]byte, error) {
+	snapshots, err := k.snapshots(func(key K, l Limiter) ([]byte, error) {
+		m, ok := l.(encoding.BinaryMarshaler)
+		if !ok {
+			return nil, fmt.Errorf("limiter for key %v cannot be marshalled", key)
+		}
+		return m.MarshalBinary()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(snapshots); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (k *KeyedLimiter[K]) UnmarshalBinary(data []byte) error {
+	var snapshots []keyedSnapshot[K]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&snapshots); err != nil {
+		return err
+	}
+
+	return k.restore(snapshots, func(key K, l Limiter, state []byte) error {
+		u, ok := l.(encoding.BinaryUnmarshaler)
+		if !ok {
+			return fmt.Errorf("limiter for key %v cannot be unmarshalled", key)
+		}
+		return u.UnmarshalBinary(state)
+	})
+}
+
+// MarshalJSON encodes the keys like MarshalBinary, but the limiters must implement json.Marshaler.
+func (k *KeyedLimiter[K]) MarshalJSON() ([]byte, error) {
+	snapshots, err := k.snapshots(func(key K, l Limiter) ([]byte, error) {
+		m, ok := l.(json.Marshaler)
+		if !ok {
+			return nil, fmt.Errorf("limiter for key %v cannot be marshalled", key)
+		}
+		return m.MarshalJSON()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(snapshots)
+}
+
+func (k *KeyedLimiter[K]) UnmarshalJSON(data []byte) error {
+	var snapshots []keyedSnapshot[K]
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return err
+	}
+
+	return k.restore(snapshots, func(key K, l Limiter, state []byte) error {
+		u, ok := l.(json.Unmarshaler)
+		if !ok {
+			return fmt.Errorf("limiter for key %v cannot be unmarshalled", key)
+		}
+		return u.UnmarshalJSON(state)
+	})
+}
+
+func (k *KeyedLimiter[K]) snapshots(marshal func(key K, l Limiter) ([]byte, error)) ([]keyedSnapshot[K], error) {
+	k.Lock()
+	defer k.Unlock()
+
+	snapshots := make([]keyedSnapshot[K], 0, len(k.entries))
+	for key, e := range k.entries {
+		state, err := marshal(key, e.limiter)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, keyedSnapshot[K]{Key: key, LastUsed: e.lastUsed, State: state})
+	}
+	return snapshots, nil
+}
+
+// restore replaces the keys with the snapshots. The limiters that are dropped, either the ones being replaced or
+// the ones created for the snapshots if one of them cannot be restored, are stopped.
+func (k *KeyedLimiter[K]) restore(snapshots []keyedSnapshot[K], unmarshal func(key K, l Limiter, state []byte) error) error {
+	entries := make(map[K]*keyedEntry, len(snapshots))
+	for _, s := range snapshots {
+		l := k.newLimiter()
+		if old, ok := entries[s.Key]; ok {
+			stopLimiter(old.limiter)
+		}
+		entries[s.Key] = &keyedEntry{limiter: l, lastUsed: s.LastUsed}
+		if err := unmarshal(s.Key, l, s.State); err != nil {
+			for _, e := range entries {
+				stopLimiter(e.limiter)
+			}
+			return err
+		}
+	}
+
+	k.Lock()
+	defer k.Unlock()
+
+	for _, e := range k.entries {
+		stopLimiter(e.limiter)
+	}
+	k.entries = entries
+	return nil
+}
@@ -0,0 +1,113 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyedLimiter(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	limiter := NewKeyedLimiter[string](func() Limiter {
+		return NewFixedWindow(1*time.Minute, 0, 2)
+	}, 5*time.Minute)
+
+	t.Run("keys are limited independently", func(t *testing.T) {
+		assert.True(t, limiter.Take("a", 2).Allowed)
+		assert.False(t, limiter.Take("a", 1).Allowed)
+		assert.True(t, limiter.Take("b", 1).Allowed)
+		assert.Equal(t, 2, limiter.Len())
+	})
+
+	t.Run("peek does not take", func(t *testing.T) {
+		assert.True(t, limiter.Peek("b", 1).Allowed)
+		assert.True(t, limiter.Peek("b", 1).Allowed)
+		assert.Equal(t, 1, limiter.Peek("b", 0).Remaining)
+	})
+
+	t.Run("evict only removes idle keys", func(t *testing.T) {
+		setFakeNow(fakeNow.Add(4 * time.Minute))
+		limiter.Take("b", 1)
+		setFakeNow(fakeNow.Add(6 * time.Minute))
+
+		assert.Equal(t, 1, limiter.Evict())
+		assert.Equal(t, 1, limiter.Len())
+	})
+
+	t.Run("idle keys are evicted while taking", func(t *testing.T) {
+		setFakeNow(fakeNow.Add(20 * time.Minute))
+
+		limiter.Take("c", 1)
+
+		assert.Equal(t, 1, limiter.Len())
+	})
+}
+
+func TestKeyedLimiter_Reserve(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	t.Run("reservations refund the limiter of the key", func(t *testing.T) {
+		limiter := NewKeyedLimiter[string](func() Limiter {
+			return NewFixedWindow(1*time.Minute, 0, 2)
+		}, 5*time.Minute)
+
+		res, reservation := limiter.Reserve("a", 2)
+		require.True(t, res.Allowed)
+		require.NotNil(t, reservation)
+		assert.False(t, limiter.Peek("a", 1).Allowed)
+
+		reservation.Cancel()
+
+		assert.True(t, limiter.Peek("a", 2).Allowed)
+		res, reservation = limiter.Reserve("a", 3)
+		assert.False(t, res.Allowed)
+		assert.Nil(t, reservation)
+	})
+
+	t.Run("limiters that cannot be reserved are taken without a reservation", func(t *testing.T) {
+		limiter := NewKeyedLimiter[string](func() Limiter {
+			return &takeRejectingLimiter{}
+		}, 5*time.Minute)
+
+		res, reservation := limiter.Reserve("a", 1)
+
+		assert.False(t, res.Allowed)
+		assert.Nil(t, reservation)
+	})
+}
+
+// stoppableLimiter is a limiter that does work in the background until it is stopped, like SlidingWindow.
+type stoppableLimiter struct {
+	*FixedWindow
+	stopped bool
+}
+
+func (l *stoppableLimiter) Stop() {
+	l.stopped = true
+}
+
+func TestKeyedLimiter_EvictStops(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	var limiters []*stoppableLimiter
+	limiter := NewKeyedLimiter[string](func() Limiter {
+		l := &stoppableLimiter{FixedWindow: NewFixedWindow(1*time.Minute, 0, 2)}
+		limiters = append(limiters, l)
+		return l
+	}, 5*time.Minute)
+
+	limiter.Take("a", 1)
+	setFakeNow(fakeNow.Add(4 * time.Minute))
+	limiter.Take("b", 1)
+	setFakeNow(fakeNow.Add(6 * time.Minute))
+
+	assert.Equal(t, 1, limiter.Evict())
+	assert.True(t, limiters[0].stopped)
+	assert.False(t, limiters[1].stopped)
+}
@@ -1,11 +1,29 @@
 package strategy
 
 import (
+	"encoding/json"
+	"math"
 	"time"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
 )
 
 var now = time.Now
 
+// SetClock replaces the clock used by the limiters, including datastruct.TokenBucket, for simulations that run
+// faster than real time. It returns a function that restores the previous clock. It must not be called while
+// limiters are in use. SlidingWindow slides its windows by the clock whenever it is used, but LeakyBucketChan
+// leaks on a real ticker and cannot be simulated.
+func SetClock(clock func() time.Time) (restore func()) {
+	prev := now
+	now = clock
+	restoreTokenBucket := datastruct.SetClock(clock)
+	return func() {
+		now = prev
+		restoreTokenBucket()
+	}
+}
+
 type LeakyBucket struct {
 	capacity      int
 	current       int
@@ -13,8 +31,9 @@ type LeakyBucket struct {
 	lastUpdated   time.Time
 }
 
+// NewLeakyBucket returns a bucket that leaks tokensPerInterval every interval.
 func NewLeakyBucket(tokensPerInterval int, interval time.Duration, capacity int) *LeakyBucket {
-	ratePerSecond := interval.Seconds() * float64(tokensPerInterval)
+	ratePerSecond := float64(tokensPerInterval) / interval.Seconds()
 	return &LeakyBucket{
 		current:       0,
 		leakPerSecond: ratePerSecond,
@@ -39,11 +58,94 @@ func (b *LeakyBucket) AddN(n int) (bool, int) {
 		spillover = abs(b.capacity - count - n)
 	}
 
-	b.current = min(b.capacity, b.current+n)
+	b.current = min(b.capacity, count+n)
 	b.lastUpdated = now()
 	return success, spillover
 }
 
+func (b *LeakyBucket) Peek(n int) Result {
+	return b.check(b.Count(), n)
+}
+
+// Take adds n if it fits within capacity. Unlike AddN, nothing is added when n does not fit.
+func (b *LeakyBucket) Take(n int) Result {
+	count := b.Count()
+	res := b.check(count, n)
+	if res.Allowed {
+		b.current = count + n
+		b.lastUpdated = now()
+	}
+	return res
+}
+
+func (b *LeakyBucket) check(count int, n int) Result {
+	res := countResult(count, b.capacity, n)
+	if !res.Allowed {
+		res.RetryAfter = b.leakDuration(count + n - b.capacity)
+	}
+	res.ResetAfter = b.leakDuration(count + allowedN(res, n))
+	return res
+}
+
+// leakDuration returns how long it takes for n to leak out of the bucket.
+func (b *LeakyBucket) leakDuration(n int) time.Duration {
+	return time.Duration(math.Ceil(float64(n) / b.leakPerSecond * float64(time.Second)))
+}
+
+// ReserveN adds n if it fits within capacity and returns a reservation that can take it back out of the bucket.
+// Unlike AddN, nothing is added when n does not fit.
+func (b *LeakyBucket) ReserveN(n int) (*Reservation, bool) {
+	_, r := b.Reserve(n)
+	return r, r != nil
+}
+
+func (b *LeakyBucket) Reserve(n int) (Result, *Reservation) {
+	res := b.Take(n)
+	if !res.Allowed {
+		return res, nil
+	}
+	return res, newReservation(n, func(n int) {
+		b.current = max(0, b.current-n)
+	})
+}
+
+// MarshalBinary encodes what is in the bucket and when it was last updated, so that what leaked out while the
+// bucket was not in use is taken into account when it is restored.
+func (b *LeakyBucket) MarshalBinary() ([]byte, error) {
+	return b.state().MarshalBinary()
+}
+
+func (b *LeakyBucket) UnmarshalBinary(data []byte) error {
+	var s leakyBucketState
+	if err := s.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	b.restore(s)
+	return nil
+}
+
+func (b *LeakyBucket) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.state())
+}
+
+func (b *LeakyBucket) UnmarshalJSON(data []byte) error {
+	var s leakyBucketState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	b.restore(s)
+	return nil
+}
+
+func (b *LeakyBucket) state() leakyBucketState {
+	return leakyBucketState{Current: b.current, LastUpdated: b.lastUpdated}
+}
+
+func (b *LeakyBucket) restore(s leakyBucketState) {
+	b.current = min(b.capacity, s.Current)
+	b.lastUpdated = s.LastUpdated
+}
+
 func min(x, y int) int {
 	if x < y {
 		return x
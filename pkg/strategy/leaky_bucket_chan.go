@@ -13,6 +13,7 @@ type LeakyBucketChan[T any] struct {
 	interval     time.Duration
 	pollInterval time.Duration
 	running      bool
+	observer     Observer
 }
 
 func NewLeakyBucketChan[T any](out chan<- T, cap int, ratePerSecond int) *LeakyBucketChan[T] {
@@ -24,15 +25,60 @@ func NewLeakyBucketChan[T any](out chan<- T, cap int, ratePerSecond int) *LeakyB
 	}
 }
 
+// TryNewLeakyBucketChan is NewLeakyBucketChan, but returns an error instead of panicking if cap is negative or
+// ratePerSecond is not from 1 to 1 per nanosecond.
+func TryNewLeakyBucketChan[T any](out chan<- T, cap int, ratePerSecond int) (*LeakyBucketChan[T], error) {
+	if cap < 0 {
+		return nil, errors.New("capacity must not be negative")
+	}
+	if ratePerSecond <= 0 {
+		return nil, errors.New("rate must be greater than 0")
+	}
+	if ratePerSecond > int(time.Second) {
+		return nil, errors.New("rate must not be more than 1 per nanosecond")
+	}
+	return NewLeakyBucketChan(out, cap, ratePerSecond), nil
+}
+
+// WithObserver calls observer with an EventAllow or EventDeny for every task enqueued, with the space left in
+// the queue as the remaining capacity.
+func (b *LeakyBucketChan[T]) WithObserver(observer Observer) *LeakyBucketChan[T] {
+	b.Lock()
+	defer b.Unlock()
+
+	b.observer = observer
+	return b
+}
+
 func (b *LeakyBucketChan[T]) Enqueue(task T) error {
+	b.RLock()
+	observer := b.observer
+	b.RUnlock()
+
 	select {
 	case b.in <- task:
+		if observer != nil {
+			observer.Observe(Event{Kind: EventAllow, Cost: 1, Remaining: cap(b.in) - len(b.in)})
+		}
 		return nil
 	default:
+		if observer != nil {
+			observer.Observe(Event{Kind: EventDeny, Cost: 1})
+		}
 		return errors.New("queue is full")
 	}
 }
 
+// Len returns how many tasks are waiting in the queue.
+func (b *LeakyBucketChan[T]) Len() int {
+	return len(b.in)
+}
+
+// Cap returns how many tasks the queue can hold.
+func (b *LeakyBucketChan[T]) Cap() int {
+	return cap(b.in)
+}
+
 func (b *LeakyBucketChan[T]) Start() {
 	b.Lock()
 	defer b.Unlock()
@@ -88,3 +88,27 @@ func TestLeakyBucketChan(t *testing.T) {
 		})
 	}
 }
+
+func TestTryNewLeakyBucketChan(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		capacity      int
+		ratePerSecond int
+		wantErr       error
+	}{
+		{desc: "valid", capacity: 1, ratePerSecond: 10},
+		{desc: "zero capacity", capacity: 0, ratePerSecond: 10},
+		{desc: "negative capacity", capacity: -1, ratePerSecond: 10, wantErr: errors.New("capacity must not be negative")},
+		{desc: "zero rate", capacity: 1, ratePerSecond: 0, wantErr: errors.New("rate must be greater than 0")},
+		{desc: "negative rate", capacity: 1, ratePerSecond: -1, wantErr: errors.New("rate must be greater than 0")},
+		{desc: "rate over 1 per nanosecond", capacity: 1, ratePerSecond: int(time.Second) + 1, wantErr: errors.New("rate must not be more than 1 per nanosecond")},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			bucket, err := TryNewLeakyBucketChan(make(chan int), tC.capacity, tC.ratePerSecond)
+
+			assert.Equal(t, tC.wantErr, err)
+			assert.Equal(t, tC.wantErr == nil, bucket != nil)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/edfoh/data-structures/pkg/datastruct"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,7 +38,7 @@ func TestLeakyBucket(t *testing.T) {
 		},
 		{
 			desc:              "when adding to bucket at capacity, should succeed without spillover",
-			tokensPerInterval: 1,
+			tokensPerInterval: 100,
 			interval:          10 * time.Second,
 			capacity:          100,
 			fakeTimeElapsed:   fakeNow.Add(9 * time.Second),
@@ -48,7 +49,7 @@ func TestLeakyBucket(t *testing.T) {
 		},
 		{
 			desc:              "when adding to bucket that just tips over capacity, should fail with 1 spillover",
-			tokensPerInterval: 1,
+			tokensPerInterval: 100,
 			interval:          10 * time.Second,
 			capacity:          100,
 			fakeTimeElapsed:   fakeNow.Add(9 * time.Second),
@@ -59,7 +60,7 @@ func TestLeakyBucket(t *testing.T) {
 		},
 		{
 			desc:              "when adding full capacity to bucket that is at half capacity, should fail with half capacity spillover",
-			tokensPerInterval: 1,
+			tokensPerInterval: 100,
 			interval:          10 * time.Second,
 			capacity:          100,
 			fakeTimeElapsed:   fakeNow.Add(5 * time.Second),
@@ -70,7 +71,7 @@ func TestLeakyBucket(t *testing.T) {
 		},
 		{
 			desc:              "when empty initially and adding to capacity, should succeed",
-			tokensPerInterval: 1,
+			tokensPerInterval: 100,
 			interval:          10 * time.Second,
 			capacity:          100,
 			fakeTimeElapsed:   fakeNow.Add(1 * time.Second),
@@ -97,6 +98,40 @@ func TestLeakyBucket(t *testing.T) {
 	}
 }
 
+func TestLeakyBucket_AddN_AfterLeaking(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	bucket := NewLeakyBucket(1, 1*time.Second, 10)
+	success, _ := bucket.AddN(8)
+	assert.True(t, success)
+	setFakeNow(fakeNow.Add(5 * time.Second))
+
+	success, spillover := bucket.AddN(2)
+
+	// what leaked out is not added back when adding
+	assert.True(t, success)
+	assert.Equal(t, 0, spillover)
+	assert.Equal(t, 5, bucket.Count())
+}
+
+func TestSetClock(t *testing.T) {
+	restore := SetClock(func() time.Time { return fakeNow })
+
+	bucket := NewLeakyBucket(1, 1*time.Second, 10)
+	tokenBucket := NewTokenBucketLimiter(datastruct.NewTokenBucket(10, 1))
+	bucket.AddN(10)
+	tokenBucket.Take(10)
+	SetClock(func() time.Time { return fakeNow.Add(4 * time.Second) })
+
+	// both the limiters and the token bucket they are built on use the clock
+	assert.Equal(t, 6, bucket.Count())
+	assert.Equal(t, 4, tokenBucket.Peek(0).Remaining)
+
+	restore()
+	assert.WithinDuration(t, time.Now(), now(), 1*time.Second)
+}
+
 func setFakeNow(t time.Time) {
 	now = func() time.Time {
 		return t
@@ -114,3 +149,33 @@ func fakeTimeSetup(t *testing.T) func() {
 		now = time.Now
 	}
 }
+
+func TestLeakyBucket_Take(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	// 1 token per second interval leaks 1 per second
+	bucket := NewLeakyBucket(1, 1*time.Second, 10)
+
+	t.Run("take within capacity is allowed", func(t *testing.T) {
+		res := bucket.Take(8)
+
+		assert.Equal(t, Result{Allowed: true, Limit: 10, Remaining: 2, ResetAfter: 8 * time.Second}, res)
+	})
+
+	t.Run("take over capacity is rejected without filling the bucket", func(t *testing.T) {
+		res := bucket.Take(5)
+
+		assert.Equal(t, Result{Allowed: false, Limit: 10, Remaining: 2, RetryAfter: 3 * time.Second, ResetAfter: 8 * time.Second}, res)
+		assert.Equal(t, 8, bucket.Count())
+	})
+
+	t.Run("take after leaking only counts what is left in the bucket", func(t *testing.T) {
+		setFakeNow(fakeNow.Add(5 * time.Second))
+
+		res := bucket.Take(5)
+
+		assert.Equal(t, Result{Allowed: true, Limit: 10, Remaining: 2, ResetAfter: 8 * time.Second}, res)
+		assert.Equal(t, 8, bucket.Count())
+	})
+}
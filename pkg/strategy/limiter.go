@@ -0,0 +1,89 @@
+package strategy
+
+import (
+	"math"
+	"time"
+)
+
+// Result describes the outcome of checking a cost of n against a limit.
+type Result struct {
+	Allowed bool
+	// Limit is the maximum that can be admitted at once.
+	Limit int
+	// Remaining is how much can still be admitted, after n was taken if it was allowed.
+	Remaining int
+	// RetryAfter is how long to wait until n is expected to be allowed, 0 if it was allowed.
+	RetryAfter time.Duration
+	// ResetAfter is how long until the full limit is available again.
+	ResetAfter time.Duration
+}
+
+// Limiter is implemented by the strategies that can be checked without being charged, so that several of them
+// can be combined or plugged in without knowing the algorithm.
+type Limiter interface {
+	// Peek returns the result of taking n without recording it.
+	Peek(n int) Result
+	// Take records n if it is allowed.
+	Take(n int) Result
+}
+
+// slidingCount returns the weighted count of the previous and current windows when elapsed time has passed since
+// the current window started.
+func slidingCount(prev int, curr int, elapsed time.Duration, interval time.Duration) float64 {
+	currCount := float64(elapsed) / float64(interval) * float64(curr)
+	prevCount := float64(interval-elapsed) / float64(interval) * float64(prev)
+	return currCount + prevCount
+}
+
+// slidingRetryAfter returns how long until the weighted count has dropped enough for n to fit within capacity,
+// assuming nothing else is added in the meantime.
+func slidingRetryAfter(prev int, curr int, elapsed time.Duration, interval time.Duration, capacity int, n int) time.Duration {
+	target := float64(capacity - n)
+	remaining := interval - elapsed
+	if target < 0 {
+		// n can never fit, so the best we can do is wait until both windows are empty
+		return remaining + interval
+	}
+
+	// the count in the current window falls if prev outweighs curr
+	if p, c := float64(prev), float64(curr); p > c {
+		f := (p - target) / (p - c)
+		if wait := time.Duration(math.Ceil(f*float64(interval))) - elapsed; f <= 1 {
+			return maxDuration(0, wait)
+		}
+	}
+
+	// once curr becomes prev, the count falls as the next window progresses
+	if curr == 0 {
+		return remaining
+	}
+	f := 1 - target/float64(curr)
+	return remaining + maxDuration(0, time.Duration(math.Ceil(f*float64(interval))))
+}
+
+// slidingResetAfter returns how long until the weighted count of both windows drops to 0.
+func slidingResetAfter(prev int, curr int, elapsed time.Duration, interval time.Duration) time.Duration {
+	if curr > 0 {
+		return interval - elapsed + interval
+	}
+	if prev > 0 {
+		return interval - elapsed
+	}
+	return 0
+}
+
+// countResult returns the result of adding n to count, without RetryAfter which depends on the strategy.
+func countResult(count int, capacity int, n int) Result {
+	if count+n > capacity {
+		return Result{Limit: capacity, Remaining: max(0, capacity-count)}
+	}
+	return Result{Allowed: true, Limit: capacity, Remaining: capacity - count - n}
+}
+
+// allowedN returns n if the result allowed it, otherwise 0.
+func allowedN(res Result, n int) int {
+	if res.Allowed {
+		return n
+	}
+	return 0
+}
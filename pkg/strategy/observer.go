@@ -0,0 +1,101 @@
+package strategy
+
+import "time"
+
+type EventKind int
+
+const (
+	// EventAllow is a take that was allowed.
+	EventAllow EventKind = iota
+	// EventDeny is a take that was rejected.
+	EventDeny
+	// EventWait is a caller waiting before trying to take again.
+	EventWait
+	// EventEvict is a key removed from a keyed limiter after being idle.
+	EventEvict
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventAllow:
+		return "allow"
+	case EventDeny:
+		return "deny"
+	case EventWait:
+		return "wait"
+	case EventEvict:
+		return "evict"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes something a limiter did. Key is empty for limiters that are not keyed, and Wait is only set
+// for EventWait.
+type Event struct {
+	Kind      EventKind
+	Key       string
+	Cost      int
+	Remaining int
+	Wait      time.Duration
+}
+
+// Observer is called for every event. It is called while the limiter is locked, so it must not call back into
+// the limiter and should return quickly.
+type Observer interface {
+	Observe(e Event)
+}
+
+type ObserverFunc func(e Event)
+
+func (f ObserverFunc) Observe(e Event) {
+	f(e)
+}
+
+// ObservedLimiter calls the observer with an EventAllow or EventDeny for every take from the limiter it wraps.
+type ObservedLimiter struct {
+	limiter  Limiter
+	observer Observer
+}
+
+func NewObservedLimiter(limiter Limiter, observer Observer) *ObservedLimiter {
+	return &ObservedLimiter{
+		limiter:  limiter,
+		observer: observer,
+	}
+}
+
+func (l *ObservedLimiter) Peek(n int) Result {
+	return l.limiter.Peek(n)
+}
+
+func (l *ObservedLimiter) Take(n int) Result {
+	res := l.limiter.Take(n)
+	l.observer.Observe(resultEvent("", n, res))
+	return res
+}
+
+// Reserve takes n like Take, returning the reservation of the limiter it wraps. The reservation is always nil if
+// that limiter is not a ReservingLimiter.
+func (l *ObservedLimiter) Reserve(n int) (Result, *Reservation) {
+	r, ok := l.limiter.(ReservingLimiter)
+	if !ok {
+		return l.Take(n), nil
+	}
+	res, reservation := r.Reserve(n)
+	l.observer.Observe(resultEvent("", n, res))
+	return res, reservation
+}
+
+func resultEvent(key string, n int, res Result) Event {
+	kind := EventAllow
+	if !res.Allowed {
+		kind = EventDeny
+	}
+	return Event{
+		Kind:      kind,
+		Key:       key,
+		Cost:      n,
+		Remaining: res.Remaining,
+	}
+}
@@ -0,0 +1,71 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingObserver struct {
+	events []Event
+}
+
+func (o *recordingObserver) Observe(e Event) {
+	o.events = append(o.events, e)
+}
+
+func TestObservedLimiter(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	observer := &recordingObserver{}
+	limiter := NewObservedLimiter(NewFixedWindow(1*time.Minute, 0, 3), observer)
+
+	limiter.Take(2)
+	limiter.Peek(2)
+	limiter.Take(2)
+
+	assert.Equal(t, []Event{
+		{Kind: EventAllow, Cost: 2, Remaining: 1},
+		{Kind: EventDeny, Cost: 2, Remaining: 1},
+	}, observer.events)
+}
+
+func TestKeyedLimiter_WithObserver(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	observer := &recordingObserver{}
+	limiter := NewKeyedLimiter[int](func() Limiter {
+		return NewFixedWindow(1*time.Minute, 0, 1)
+	}, 5*time.Minute).WithObserver(observer)
+
+	limiter.Take(1, 1)
+	limiter.Take(1, 1)
+	setFakeNow(fakeNow.Add(6 * time.Minute))
+	limiter.Evict()
+
+	assert.Equal(t, []Event{
+		{Kind: EventAllow, Key: "1", Cost: 1, Remaining: 0},
+		{Kind: EventDeny, Key: "1", Cost: 1, Remaining: 0},
+		{Kind: EventEvict, Key: "1"},
+	}, observer.events)
+}
+
+func TestLeakyBucketChan_WithObserver(t *testing.T) {
+	observer := &recordingObserver{}
+	b := NewLeakyBucketChan[int](make(chan int), 2, 1).WithObserver(observer)
+
+	b.Enqueue(1)
+	b.Enqueue(2)
+	b.Enqueue(3)
+
+	assert.Equal(t, 2, b.Len())
+	assert.Equal(t, 2, b.Cap())
+	assert.Equal(t, []Event{
+		{Kind: EventAllow, Cost: 1, Remaining: 1},
+		{Kind: EventAllow, Cost: 1, Remaining: 0},
+		{Kind: EventDeny, Cost: 1},
+	}, observer.events)
+}
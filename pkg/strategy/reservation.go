@@ -0,0 +1,84 @@
+package strategy
+
+import (
+	"sync"
+	"time"
+)
+
+// Reservation is a charge that has been taken from a limit and can be refunded, e.g. when the request it was
+// taken for fails early or is cancelled.
+type Reservation struct {
+	sync.Mutex
+	n      int
+	refund func(n int)
+}
+
+func newReservation(n int, refund func(n int)) *Reservation {
+	return &Reservation{
+		n:      n,
+		refund: refund,
+	}
+}
+
+// N returns how much of the reservation has not been refunded.
+func (r *Reservation) N() int {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.n
+}
+
+// Refund gives back n of the reservation to the limit it was taken from, up to what has not been refunded yet.
+func (r *Reservation) Refund(n int) {
+	r.Lock()
+	defer r.Unlock()
+
+	n = min(n, r.n)
+	if n <= 0 {
+		return
+	}
+	r.n -= n
+	r.refund(n)
+}
+
+// Cancel refunds everything that has not been refunded yet.
+func (r *Reservation) Cancel() {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.n > 0 {
+		r.refund(r.n)
+		r.n = 0
+	}
+}
+
+// ReservingLimiter is a Limiter that can hand back a reservation for what it takes, so that CompositeLimiter can
+// refund it when a later limit rejects.
+type ReservingLimiter interface {
+	Limiter
+	// Reserve takes n like Take and returns a reservation for it, which is nil if n was not allowed.
+	Reserve(n int) (Result, *Reservation)
+}
+
+// asReserving returns l if it can hand back reservations. ObservedLimiter implements Reserve whatever it wraps, so
+// it only counts if the limiter it wraps can.
+func asReserving(l Limiter) (ReservingLimiter, bool) {
+	if o, ok := l.(*ObservedLimiter); ok {
+		if _, ok := asReserving(o.limiter); !ok {
+			return nil, false
+		}
+	}
+	r, ok := l.(ReservingLimiter)
+	return r, ok
+}
+
+// refundWindow removes n from whichever window started at startTime. If both windows have since slid past it,
+// the charge no longer counts towards the limit and there is nothing to refund.
+func refundWindow(prev *window, curr *window, startTime time.Time, n int) {
+	for _, w := range []*window{curr, prev} {
+		if w.StartTime().Equal(startTime) {
+			w.SetCount(max(0, w.Count()-n))
+			return
+		}
+	}
+}
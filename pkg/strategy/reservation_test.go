@@ -0,0 +1,202 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservation_Refund(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		reserveN      int
+		refunds       []int
+		wantRefunded  []int
+		wantRemaining int
+	}{
+		{
+			desc:          "refund part of the reservation",
+			reserveN:      5,
+			refunds:       []int{2},
+			wantRefunded:  []int{2},
+			wantRemaining: 3,
+		},
+		{
+			desc:          "refunds are capped to what is left of the reservation",
+			reserveN:      5,
+			refunds:       []int{3, 3, 1},
+			wantRefunded:  []int{3, 2},
+			wantRemaining: 0,
+		},
+		{
+			desc:          "refunding nothing or a negative amount does nothing",
+			reserveN:      5,
+			refunds:       []int{0, -1},
+			wantRefunded:  nil,
+			wantRemaining: 5,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var gotRefunded []int
+			r := newReservation(tC.reserveN, func(n int) {
+				gotRefunded = append(gotRefunded, n)
+			})
+
+			for _, refund := range tC.refunds {
+				r.Refund(refund)
+			}
+
+			assert.Equal(t, tC.wantRefunded, gotRefunded)
+			assert.Equal(t, tC.wantRemaining, r.N())
+		})
+	}
+}
+
+func TestSyncSlidingWindow_ReserveN(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	sl := NewSyncSlidingWindow(1*time.Minute, 10)
+
+	first, ok := sl.ReserveN(5)
+	require.True(t, ok)
+	second, ok := sl.ReserveN(4)
+	require.True(t, ok)
+
+	t.Run("reserve over capacity fails", func(t *testing.T) {
+		setFakeNow(fakeNow.Add(59 * time.Second))
+
+		r, ok := sl.ReserveN(2)
+
+		assert.False(t, ok)
+		assert.Nil(t, r)
+	})
+
+	t.Run("cancel refunds the current window", func(t *testing.T) {
+		first.Cancel()
+
+		gotPrevCount, gotCurrCount := sl.Count()
+		assert.Equal(t, 0, gotPrevCount)
+		assert.Equal(t, 4, gotCurrCount)
+	})
+
+	t.Run("refund after sliding refunds the previous window", func(t *testing.T) {
+		setFakeNow(fakeNow.Add(70 * time.Second))
+		_, ok := sl.ReserveN(1)
+		require.True(t, ok)
+
+		second.Refund(3)
+
+		gotPrevCount, gotCurrCount := sl.Count()
+		assert.Equal(t, 1, gotPrevCount)
+		assert.Equal(t, 1, gotCurrCount)
+	})
+
+	t.Run("refund after the window has slid out does nothing", func(t *testing.T) {
+		setFakeNow(fakeNow.Add(200 * time.Second))
+		_, ok := sl.ReserveN(2)
+		require.True(t, ok)
+
+		second.Cancel()
+
+		gotPrevCount, gotCurrCount := sl.Count()
+		assert.Equal(t, 0, gotPrevCount)
+		assert.Equal(t, 2, gotCurrCount)
+		assert.Equal(t, 0, second.N())
+	})
+}
+
+func TestSlidingWindow_ReserveN(t *testing.T) {
+	sl := NewSlidingWindow(1*time.Minute, 10)
+	defer sl.Stop()
+
+	r, ok := sl.ReserveN(8)
+	require.True(t, ok)
+
+	r.Refund(5)
+
+	gotPrevCount, gotCurrCount := sl.GetCount()
+	assert.Equal(t, 0, gotPrevCount)
+	assert.Equal(t, 3, gotCurrCount)
+}
+
+func TestLeakyBucket_ReserveN(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	bucket := NewLeakyBucket(1, 1*time.Second, 10)
+
+	r, ok := bucket.ReserveN(8)
+	require.True(t, ok)
+	assert.Equal(t, 8, bucket.Count())
+
+	t.Run("reserve over capacity fails without filling the bucket", func(t *testing.T) {
+		r, ok := bucket.ReserveN(3)
+
+		assert.False(t, ok)
+		assert.Nil(t, r)
+		assert.Equal(t, 8, bucket.Count())
+	})
+
+	t.Run("refund takes back out of the bucket", func(t *testing.T) {
+		r.Refund(6)
+
+		assert.Equal(t, 2, bucket.Count())
+	})
+}
+
+func TestFixedWindow_ReserveN(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	w := NewFixedWindow(1*time.Minute, 0, 10)
+
+	r, ok := w.ReserveN(8)
+	require.True(t, ok)
+
+	r.Refund(5)
+	assert.Equal(t, 3, w.Count())
+
+	t.Run("refund after the window has ended does nothing", func(t *testing.T) {
+		setFakeNow(fakeNow.Add(1 * time.Minute))
+		require.True(t, w.Take(2).Allowed)
+
+		r.Cancel()
+
+		assert.Equal(t, 2, w.Count())
+	})
+}
+
+func TestGCRA_ReserveN(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	g, err := NewGCRA(10, 1*time.Second, 5)
+	require.NoError(t, err)
+
+	r, ok := g.ReserveN(5)
+	require.True(t, ok)
+	assert.False(t, g.Peek(1).Allowed)
+
+	r.Refund(2)
+
+	assert.Equal(t, 2, g.Peek(0).Remaining)
+}
+
+func TestTokenBucketLimiter_ReserveN(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	l := NewTokenBucketLimiter(datastruct.NewTokenBucket(10, 1))
+
+	r, ok := l.ReserveN(8)
+	require.True(t, ok)
+
+	r.Cancel()
+
+	assert.Equal(t, 10, l.Peek(0).Remaining)
+}
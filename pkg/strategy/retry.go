@@ -0,0 +1,190 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+)
+
+// BackoffPolicy decides how long to wait before the next attempt, from the attempt that just failed, counting
+// from 1, and the previous wait, which is 0 after the first attempt.
+type BackoffPolicy interface {
+	Backoff(attempt int, prev time.Duration) time.Duration
+}
+
+// ExponentialBackoff doubles the wait after every attempt, starting at base and capped at max.
+type ExponentialBackoff struct {
+	base time.Duration
+	max  time.Duration
+}
+
+func NewExponentialBackoff(base time.Duration, max time.Duration) *ExponentialBackoff {
+	return &ExponentialBackoff{
+		base: base,
+		max:  max,
+	}
+}
+
+func (b *ExponentialBackoff) Backoff(attempt int, prev time.Duration) time.Duration {
+	wait := float64(b.base) * math.Pow(2, float64(attempt-1))
+	return time.Duration(math.Min(wait, float64(b.max)))
+}
+
+// DecorrelatedJitterBackoff waits a random time between base and three times the previous wait, capped at max,
+// so that clients that failed at the same time do not retry at the same time.
+type DecorrelatedJitterBackoff struct {
+	base time.Duration
+	max  time.Duration
+}
+
+func NewDecorrelatedJitterBackoff(base time.Duration, max time.Duration) *DecorrelatedJitterBackoff {
+	return &DecorrelatedJitterBackoff{
+		base: base,
+		max:  max,
+	}
+}
+
+func (b *DecorrelatedJitterBackoff) Backoff(attempt int, prev time.Duration) time.Duration {
+	upper := maxDuration(b.base, 3*prev)
+	wait := b.base + time.Duration(rand.Int63n(int64(upper-b.base)+1))
+	if wait > b.max {
+		return b.max
+	}
+	return wait
+}
+
+// ConstantBackoff always waits the same time.
+type ConstantBackoff struct {
+	wait time.Duration
+}
+
+func NewConstantBackoff(wait time.Duration) *ConstantBackoff {
+	return &ConstantBackoff{
+		wait: wait,
+	}
+}
+
+func (b *ConstantBackoff) Backoff(attempt int, prev time.Duration) time.Duration {
+	return b.wait
+}
+
+// retryBudgetScale is how many tokens a retry costs, so that requests can earn a percentage of a retry.
+const retryBudgetScale = 100
+
+// RetryBudget caps retries at a percentage of requests, so that retries do not multiply the load on something
+// that is already failing. Every request adds percent tokens to a TokenBucket, and every retry takes a whole
+// retry's worth. The bucket starts full, allowing maxRetries retries before any requests were made.
+type RetryBudget struct {
+	bucket  *datastruct.TokenBucket
+	percent int
+}
+
+func NewRetryBudget(percent int, maxRetries int) *RetryBudget {
+	return &RetryBudget{
+		bucket:  datastruct.NewTokenBucket(float64(maxRetries*retryBudgetScale), 0),
+		percent: percent,
+	}
+}
+
+// Deposit records a request, which earns a percentage of a retry.
+func (b *RetryBudget) Deposit() {
+	b.bucket.Refund(b.percent)
+}
+
+// Withdraw takes a retry from the budget, returning false if there is not enough left.
+func (b *RetryBudget) Withdraw() bool {
+	return b.bucket.TakeN(retryBudgetScale) == nil
+}
+
+// Retries returns how many whole retries are left in the budget.
+func (b *RetryBudget) Retries() int {
+	return int(b.bucket.Tokens()) / retryBudgetScale
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks err as not worth retrying. Retrier.Do returns it unwrapped.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Retrier calls a function until it succeeds, waiting between attempts with a BackoffPolicy.
+type Retrier struct {
+	policy      BackoffPolicy
+	maxAttempts int
+	budget      *RetryBudget
+}
+
+// NewRetrier makes at most maxAttempts attempts, including the first.
+func NewRetrier(policy BackoffPolicy, maxAttempts int) *Retrier {
+	return &Retrier{
+		policy:      policy,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// WithBudget only retries while budget has retries left. Every call to Do is deposited in it as a request, and
+// the budget can be shared by several retriers.
+func (r *Retrier) WithBudget(budget *RetryBudget) *Retrier {
+	r.budget = budget
+	return r
+}
+
+// Do calls fn until it returns nil, an error marked as Permanent, the attempts or the budget run out, or ctx is
+// done. Errors from running out of attempts or budget wrap the last error from fn.
+func (r *Retrier) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r.budget != nil {
+		r.budget.Deposit()
+	}
+
+	var wait time.Duration
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+
+		if attempt >= r.maxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+		if r.budget != nil && !r.budget.Withdraw() {
+			return fmt.Errorf("retry budget exhausted after %d attempts: %w", attempt, err)
+		}
+
+		wait = r.policy.Backoff(attempt, wait)
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
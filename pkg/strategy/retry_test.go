@@ -0,0 +1,138 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffPolicy(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		policy BackoffPolicy
+		want   []time.Duration
+	}{
+		{
+			desc:   "exponential doubles up to the max",
+			policy: NewExponentialBackoff(100*time.Millisecond, 1*time.Second),
+			want:   []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, 1 * time.Second},
+		},
+		{
+			desc:   "constant always waits the same",
+			policy: NewConstantBackoff(50 * time.Millisecond),
+			want:   []time.Duration{50 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			var got []time.Duration
+			var wait time.Duration
+			for attempt := 1; attempt <= len(tC.want); attempt++ {
+				wait = tC.policy.Backoff(attempt, wait)
+				got = append(got, wait)
+			}
+
+			assert.Equal(t, tC.want, got)
+		})
+	}
+}
+
+func TestDecorrelatedJitterBackoff(t *testing.T) {
+	policy := NewDecorrelatedJitterBackoff(10*time.Millisecond, 1*time.Second)
+
+	var wait time.Duration
+	for attempt := 1; attempt <= 100; attempt++ {
+		prev := wait
+		wait = policy.Backoff(attempt, prev)
+
+		assert.GreaterOrEqual(t, wait, 10*time.Millisecond)
+		assert.LessOrEqual(t, wait, maxDuration(10*time.Millisecond, 3*prev))
+		assert.LessOrEqual(t, wait, 1*time.Second)
+	}
+}
+
+func TestRetrier_Do(t *testing.T) {
+	errFailed := errors.New("failed")
+	retrier := NewRetrier(NewConstantBackoff(1*time.Millisecond), 3)
+
+	testCases := []struct {
+		desc         string
+		errs         []error
+		wantAttempts int
+		wantErr      string
+	}{
+		{
+			desc:         "succeeds after failing",
+			errs:         []error{errFailed, nil},
+			wantAttempts: 2,
+		},
+		{
+			desc:         "gives up after max attempts",
+			errs:         []error{errFailed, errFailed, errFailed, nil},
+			wantAttempts: 3,
+			wantErr:      "giving up after 3 attempts: failed",
+		},
+		{
+			desc:         "permanent errors are not retried",
+			errs:         []error{Permanent(errFailed), nil},
+			wantAttempts: 1,
+			wantErr:      "failed",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			attempts := 0
+			err := retrier.Do(context.Background(), func(ctx context.Context) error {
+				attempts++
+				return tC.errs[attempts-1]
+			})
+
+			assert.Equal(t, tC.wantAttempts, attempts)
+			if tC.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tC.wantErr)
+				assert.ErrorIs(t, err, errFailed)
+			}
+		})
+	}
+}
+
+func TestRetrier_ContextCancelled(t *testing.T) {
+	retrier := NewRetrier(NewConstantBackoff(1*time.Hour), 3)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := retrier.Do(ctx, func(ctx context.Context) error {
+		return errors.New("failed")
+	})
+
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestRetryBudget(t *testing.T) {
+	budget := NewRetryBudget(10, 2)
+	retrier := NewRetrier(NewConstantBackoff(0), 5).WithBudget(budget)
+	alwaysFail := func(ctx context.Context) error { return errors.New("failed") }
+
+	t.Run("the starting budget allows a few retries", func(t *testing.T) {
+		err := retrier.Do(context.Background(), alwaysFail)
+
+		assert.EqualError(t, err, "retry budget exhausted after 3 attempts: failed")
+		assert.Equal(t, 0, budget.Retries())
+	})
+
+	t.Run("requests earn retries at the percentage", func(t *testing.T) {
+		for i := 0; i < 19; i++ {
+			budget.Deposit()
+		}
+		assert.Equal(t, 1, budget.Retries())
+
+		err := retrier.Do(context.Background(), alwaysFail)
+
+		assert.EqualError(t, err, "retry budget exhausted after 3 attempts: failed")
+	})
+}
@@ -0,0 +1,219 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+)
+
+type semaphoreWaiter struct {
+	n     int
+	ready chan struct{}
+}
+
+// WeightedSemaphore bounds the total weight of the operations running at once. Waiters are served in the order
+// they arrived, so a large request is not starved by smaller ones that would fit before it.
+type WeightedSemaphore struct {
+	sync.Mutex
+	size    int
+	current int
+	nextID  uint64
+	waiters *datastruct.DoubleLinkedList[uint64, *semaphoreWaiter]
+}
+
+func NewWeightedSemaphore(size int) *WeightedSemaphore {
+	return &WeightedSemaphore{
+		size:    size,
+		waiters: datastruct.NewDoubleLinkedList[uint64, *semaphoreWaiter](),
+	}
+}
+
+// Acquire waits until n is available or ctx is done. On failure nothing is acquired.
+func (s *WeightedSemaphore) Acquire(ctx context.Context, n int) error {
+	s.Lock()
+	if n > s.size {
+		s.Unlock()
+		return errors.New("cannot acquire more than the size of the semaphore")
+	}
+	if s.fits(n) {
+		s.current += n
+		s.Unlock()
+		return nil
+	}
+
+	id := s.nextID
+	s.nextID++
+	w := &semaphoreWaiter{n: n, ready: make(chan struct{})}
+	// the list allows duplicates, so the insert cannot fail
+	elem, _ := s.waiters.TryInsertTail(id, w)
+	s.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	select {
+	case <-w.ready:
+		// acquired just as ctx was done, so give it back
+		s.current -= n
+	default:
+		elem.Remove()
+	}
+	// the waiters behind this one may fit now that it is gone
+	s.notifyWaiters()
+	return ctx.Err()
+}
+
+// TryAcquire acquires n only if it is available straight away and nobody is waiting.
+func (s *WeightedSemaphore) TryAcquire(n int) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.fits(n) {
+		return false
+	}
+	s.current += n
+	return true
+}
+
+func (s *WeightedSemaphore) Release(n int) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.current -= n
+	if s.current < 0 {
+		panic("semaphore: released more than was acquired")
+	}
+	s.notifyWaiters()
+}
+
+// Available returns how much can be acquired.
+func (s *WeightedSemaphore) Available() int {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.size - s.current
+}
+
+func (s *WeightedSemaphore) fits(n int) bool {
+	_, _, waiting := s.waiters.Front()
+	return !waiting && s.size-s.current >= n
+}
+
+// notifyWaiters wakes the waiters at the front of the queue that fit, stopping at the first one that does not so
+// that waiters are served in order.
+func (s *WeightedSemaphore) notifyWaiters() {
+	for {
+		_, w, ok := s.waiters.Front()
+		if !ok || s.size-s.current < w.n {
+			return
+		}
+		s.current += w.n
+		s.waiters.DeleteHead()
+		close(w.ready)
+	}
+}
+
+type bulkheadEntry struct {
+	semaphore *WeightedSemaphore
+	// users counts the callers acquiring or holding the key, so that the key is removed once it is unused
+	users int
+}
+
+// Bulkhead bounds the weight of the operations running at once for each key, within a pool shared by all keys,
+// so that one key cannot take the whole pool.
+type Bulkhead[K comparable] struct {
+	sync.Mutex
+	global  *WeightedSemaphore
+	perKey  int
+	entries map[K]*bulkheadEntry
+}
+
+func NewBulkhead[K comparable](globalSize int, perKeySize int) *Bulkhead[K] {
+	return &Bulkhead[K]{
+		global:  NewWeightedSemaphore(globalSize),
+		perKey:  perKeySize,
+		entries: make(map[K]*bulkheadEntry),
+	}
+}
+
+// Acquire waits until n is available to key, both in its own share and in the pool.
+func (b *Bulkhead[K]) Acquire(ctx context.Context, key K, n int) error {
+	e := b.use(key)
+	if err := e.semaphore.Acquire(ctx, n); err != nil {
+		b.done(key, e)
+		return err
+	}
+	if err := b.global.Acquire(ctx, n); err != nil {
+		e.semaphore.Release(n)
+		b.done(key, e)
+		return err
+	}
+	return nil
+}
+
+func (b *Bulkhead[K]) TryAcquire(key K, n int) bool {
+	e := b.use(key)
+	if !e.semaphore.TryAcquire(n) {
+		b.done(key, e)
+		return false
+	}
+	if !b.global.TryAcquire(n) {
+		e.semaphore.Release(n)
+		b.done(key, e)
+		return false
+	}
+	return true
+}
+
+// Release gives back n acquired for key. Every successful acquire must be released once with the same n.
+func (b *Bulkhead[K]) Release(key K, n int) {
+	b.Lock()
+	e, ok := b.entries[key]
+	b.Unlock()
+	if !ok {
+		panic("bulkhead: released a key that was not acquired")
+	}
+
+	b.global.Release(n)
+	e.semaphore.Release(n)
+	b.done(key, e)
+}
+
+// Len returns how many keys are acquiring or holding part of the pool.
+func (b *Bulkhead[K]) Len() int {
+	b.Lock()
+	defer b.Unlock()
+
+	return len(b.entries)
+}
+
+func (b *Bulkhead[K]) use(key K) *bulkheadEntry {
+	b.Lock()
+	defer b.Unlock()
+
+	e, ok := b.entries[key]
+	if !ok {
+		e = &bulkheadEntry{semaphore: NewWeightedSemaphore(b.perKey)}
+		b.entries[key] = e
+	}
+	e.users++
+	return e
+}
+
+func (b *Bulkhead[K]) done(key K, e *bulkheadEntry) {
+	b.Lock()
+	defer b.Unlock()
+
+	e.users--
+	if e.users == 0 {
+		delete(b.entries, key)
+	}
+}
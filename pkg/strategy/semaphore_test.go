@@ -0,0 +1,145 @@
+package strategy
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeightedSemaphore(t *testing.T) {
+	s := NewWeightedSemaphore(5)
+
+	t.Run("try acquire within the size works", func(t *testing.T) {
+		assert.True(t, s.TryAcquire(3))
+		assert.False(t, s.TryAcquire(3))
+		assert.Equal(t, 2, s.Available())
+	})
+
+	t.Run("acquiring more than the size fails", func(t *testing.T) {
+		err := s.Acquire(context.Background(), 6)
+
+		assert.EqualError(t, err, "cannot acquire more than the size of the semaphore")
+	})
+
+	t.Run("acquire waits for a release", func(t *testing.T) {
+		acquired := make(chan struct{})
+		go func() {
+			assert.NoError(t, s.Acquire(context.Background(), 4))
+			close(acquired)
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		select {
+		case <-acquired:
+			t.Fatal("acquired before the release")
+		default:
+		}
+
+		s.Release(3)
+		<-acquired
+		assert.Equal(t, 1, s.Available())
+		s.Release(4)
+	})
+
+	t.Run("releasing more than acquired panics", func(t *testing.T) {
+		assert.Panics(t, func() { s.Release(1) })
+	})
+}
+
+func TestWeightedSemaphore_FIFO(t *testing.T) {
+	s := NewWeightedSemaphore(4)
+	require.True(t, s.TryAcquire(3))
+
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	acquire := func(n int) {
+		defer wg.Done()
+		require.NoError(t, s.Acquire(context.Background(), n))
+		mu.Lock()
+		order = append(order, n)
+		mu.Unlock()
+	}
+
+	// the large request queues first, so the small one behind it must not jump ahead even though it fits
+	wg.Add(1)
+	go acquire(4)
+	time.Sleep(10 * time.Millisecond)
+	assert.False(t, s.TryAcquire(1))
+	wg.Add(1)
+	go acquire(1)
+	time.Sleep(10 * time.Millisecond)
+
+	mu.Lock()
+	assert.Empty(t, order)
+	mu.Unlock()
+
+	s.Release(3)
+	time.Sleep(10 * time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, []int{4}, order)
+	mu.Unlock()
+
+	s.Release(4)
+	wg.Wait()
+	assert.Equal(t, []int{4, 1}, order)
+}
+
+func TestWeightedSemaphore_Cancel(t *testing.T) {
+	s := NewWeightedSemaphore(4)
+	require.True(t, s.TryAcquire(3))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := s.Acquire(ctx, 4)
+
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, s.TryAcquire(1), "the cancelled waiter no longer holds up the queue")
+}
+
+func TestBulkhead(t *testing.T) {
+	b := NewBulkhead[string](5, 3)
+
+	t.Run("each key is bounded by its share", func(t *testing.T) {
+		assert.True(t, b.TryAcquire("a", 3))
+		assert.False(t, b.TryAcquire("a", 1))
+		assert.True(t, b.TryAcquire("b", 2))
+	})
+
+	t.Run("keys are bounded by the pool", func(t *testing.T) {
+		assert.False(t, b.TryAcquire("c", 1))
+		assert.Equal(t, 2, b.Len(), "keys that failed to acquire are not kept")
+	})
+
+	t.Run("acquire waits for the pool", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+		defer cancel()
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			b.Release("a", 3)
+		}()
+
+		assert.NoError(t, b.Acquire(ctx, "c", 3))
+		assert.Equal(t, 2, b.Len())
+	})
+
+	t.Run("a cancelled acquire gives back the key's share", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.Equal(t, context.DeadlineExceeded, b.Acquire(ctx, "d", 1))
+		assert.Equal(t, 2, b.Len())
+	})
+
+	t.Run("released keys are removed", func(t *testing.T) {
+		b.Release("b", 2)
+		b.Release("c", 3)
+
+		assert.Equal(t, 0, b.Len())
+		assert.Panics(t, func() { b.Release("c", 1) })
+	})
+}
@@ -2,6 +2,7 @@ package strategy
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"math"
 	"sync"
@@ -62,6 +63,8 @@ type SlidingWindow struct {
 	stopped    bool
 	interval   time.Duration
 	capacity   int
+	restored   chan struct{}
+	done       chan struct{}
 	ctx        context.Context
 	cancelFunc context.CancelFunc
 }
@@ -76,6 +79,8 @@ func NewSlidingWindow(interval time.Duration, capacity int) *SlidingWindow {
 		curr:       newWindow(currTime),
 		interval:   interval,
 		capacity:   capacity,
+		restored:   make(chan struct{}, 1),
+		done:       make(chan struct{}),
 		ctx:        ctx,
 		cancelFunc: cancelFunc,
 	}
@@ -84,60 +89,184 @@ func NewSlidingWindow(interval time.Duration, capacity int) *SlidingWindow {
 }
 
 func (w *SlidingWindow) GetCount() (int, int) {
+	w.Lock()
+	defer w.Unlock()
+
+	w.slide(now())
 	return w.prev.Count(), w.curr.Count()
 }
 
 func (w *SlidingWindow) AddN(n int) (bool, error) {
-	if w.stopped {
+	w.Lock()
+	stopped := w.stopped
+	w.Unlock()
+
+	if stopped {
 		return false, errors.New("sliding window has stopped")
 	}
+	return w.Take(n).Allowed, nil
+}
+
+func (w *SlidingWindow) Peek(n int) Result {
 	w.Lock()
 	defer w.Unlock()
 
-	tNow := now()
-	currTimeSlide := tNow.Sub(w.curr.StartTime())
-	prevTimeSlide := w.interval - currTimeSlide
-
-	prevSlideCount := float64(prevTimeSlide) / float64(w.interval) * float64(w.prev.Count())
-	currSlideCount := float64(currTimeSlide) / float64(w.interval) * float64(w.curr.Count())
+	return w.check(now(), n)
+}
 
-	windowCount := prevSlideCount + currSlideCount
+func (w *SlidingWindow) Take(n int) Result {
+	w.Lock()
+	defer w.Unlock()
 
-	if int(windowCount)+n > w.capacity {
-		return false, nil
+	res := w.check(now(), n)
+	if res.Allowed {
+		w.curr.AddN(n)
 	}
+	return res
+}
+
+// ReserveN takes n like AddN and returns a reservation that refunds to the window n was taken in.
+func (w *SlidingWindow) ReserveN(n int) (*Reservation, bool) {
+	_, r := w.Reserve(n)
+	return r, r != nil
+}
+
+func (w *SlidingWindow) Reserve(n int) (Result, *Reservation) {
+	w.Lock()
+	defer w.Unlock()
 
+	res := w.check(now(), n)
+	if !res.Allowed {
+		return res, nil
+	}
 	w.curr.AddN(n)
-	return true, nil
+
+	startTime := w.curr.StartTime()
+	return res, newReservation(n, func(n int) {
+		w.Lock()
+		defer w.Unlock()
+
+		refundWindow(w.prev, w.curr, startTime, n)
+	})
 }
 
-func (w *SlidingWindow) Stop() {
+func (w *SlidingWindow) check(t time.Time, n int) Result {
 	if w.stopped {
-		return
+		return Result{Limit: w.capacity}
 	}
 
-	w.Lock()
-	defer w.Unlock()
+	w.slide(t)
+	elapsed := t.Sub(w.curr.StartTime())
+	windowCount := slidingCount(w.prev.Count(), w.curr.Count(), elapsed, w.interval)
+
+	res := countResult(int(windowCount), w.capacity, n)
+	if !res.Allowed {
+		res.RetryAfter = slidingRetryAfter(w.prev.Count(), w.curr.Count(), elapsed, w.interval, w.capacity, n)
+	}
+	res.ResetAfter = slidingResetAfter(w.prev.Count(), w.curr.Count()+allowedN(res, n), elapsed, w.interval)
+	return res
+}
 
-	defer w.cancelFunc()
+// Stop stops sliding the windows and waits for the goroutine that slides them to exit.
+func (w *SlidingWindow) Stop() {
+	w.Lock()
 	w.stopped = true
+	w.Unlock()
+
+	w.cancelFunc()
+	<-w.done
 }
 
 func (w *SlidingWindow) processProgressive() {
+	defer close(w.done)
 	for {
+		w.Lock()
+		waitDuration := w.nextSlide(now())
+		w.Unlock()
+
+		timer := time.NewTimer(waitDuration)
 		select {
 		case <-w.ctx.Done():
+			timer.Stop()
 			return
-		default:
-			waitDuration := w.interval - now().Sub(w.curr.StartTime())
-			time.Sleep(waitDuration)
-			w.Lock()
-			// copy curr to prev and start new curr
-			w.prev.CopyFrom(w.curr)
-			w.curr.Reset()
-			w.Unlock()
+		case <-w.restored:
+			// the current window was replaced, so wait for the end of the restored one instead
+			timer.Stop()
+			continue
+		case <-timer.C:
 		}
 
+		w.Lock()
+		w.slide(now())
+		w.Unlock()
+	}
+}
+
+// slide moves the windows along to t. The windows are slid whenever they are used as well as by the goroutine, so
+// that they follow the clock set with SetClock rather than the goroutine's timer.
+func (w *SlidingWindow) slide(t time.Time) {
+	if w.stopped {
+		return
+	}
+
+	if elapsed := t.Sub(w.curr.StartTime()); elapsed >= 2*w.interval {
+		w.prev.Set(t.Add(-w.interval), 0)
+		w.curr.Set(t, 0)
+	} else if elapsed >= w.interval {
+		w.prev.CopyFrom(w.curr)
+		w.curr.Set(t, 0)
+	}
+}
+
+// nextSlide returns how long after t the current window ends.
+func (w *SlidingWindow) nextSlide(t time.Time) time.Duration {
+	return w.interval - t.Sub(w.curr.StartTime())
+}
+
+// MarshalBinary encodes both windows. When restored, the windows are slid along for the time that passed since
+// the current window started, as they would have been if the window had been running.
+func (w *SlidingWindow) MarshalBinary() ([]byte, error) {
+	return w.state().MarshalBinary()
+}
+
+func (w *SlidingWindow) UnmarshalBinary(data []byte) error {
+	var s slidingWindowState
+	if err := s.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	w.restore(s)
+	return nil
+}
+
+func (w *SlidingWindow) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.state())
+}
+
+func (w *SlidingWindow) UnmarshalJSON(data []byte) error {
+	var s slidingWindowState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	w.restore(s)
+	return nil
+}
+
+func (w *SlidingWindow) state() slidingWindowState {
+	w.Lock()
+	defer w.Unlock()
+
+	return newSlidingWindowState(w.prev, w.curr)
+}
+
+func (w *SlidingWindow) restore(s slidingWindowState) {
+	w.Lock()
+	s.restore(w.prev, w.curr)
+	w.slide(now())
+	w.Unlock()
+
+	select {
+	case w.restored <- struct{}{}:
+	default:
 	}
 }
 
@@ -172,23 +301,91 @@ func (w *SyncSlidingWindow) Count() (int, int) {
 }
 
 func (w *SyncSlidingWindow) AddN(n int) (bool, error) {
+	if !w.Take(n).Allowed {
+		return false, errors.New("sliding window is full")
+	}
+	return true, nil
+}
+
+func (w *SyncSlidingWindow) Peek(n int) Result {
 	tNow := now()
 
 	w.adjustWindows(tNow)
+	return w.check(tNow, n)
+}
 
-	currTimePortion := tNow.Sub(w.curr.StartTime())
-	prevTimePortion := w.interval - currTimePortion
+func (w *SyncSlidingWindow) Take(n int) Result {
+	tNow := now()
 
-	currTimeCount := float64(currTimePortion) / float64(w.interval) * float64(w.curr.Count())
-	prevTimeCount := float64(prevTimePortion) / float64(w.interval) * float64(w.prev.Count())
-	totalCount := currTimeCount + prevTimeCount
+	w.adjustWindows(tNow)
+	res := w.check(tNow, n)
+	if res.Allowed {
+		w.curr.AddN(n)
+	}
+	return res
+}
 
-	if int(math.Round(totalCount))+n > w.capacity {
-		return false, errors.New("sliding window is full")
+// ReserveN takes n like AddN and returns a reservation that refunds to the window n was taken in.
+func (w *SyncSlidingWindow) ReserveN(n int) (*Reservation, bool) {
+	_, r := w.Reserve(n)
+	return r, r != nil
+}
+
+func (w *SyncSlidingWindow) Reserve(n int) (Result, *Reservation) {
+	res := w.Take(n)
+	if !res.Allowed {
+		return res, nil
 	}
 
-	w.curr.AddN(n)
-	return true, nil
+	startTime := w.curr.StartTime()
+	return res, newReservation(n, func(n int) {
+		refundWindow(w.prev, w.curr, startTime, n)
+	})
+}
+
+func (w *SyncSlidingWindow) check(t time.Time, n int) Result {
+	elapsed := t.Sub(w.curr.StartTime())
+	totalCount := slidingCount(w.prev.Count(), w.curr.Count(), elapsed, w.interval)
+
+	res := countResult(int(math.Round(totalCount)), w.capacity, n)
+	if !res.Allowed {
+		res.RetryAfter = slidingRetryAfter(w.prev.Count(), w.curr.Count(), elapsed, w.interval, w.capacity, n)
+	}
+	res.ResetAfter = slidingResetAfter(w.prev.Count(), w.curr.Count()+allowedN(res, n), elapsed, w.interval)
+	return res
+}
+
+// MarshalBinary encodes both windows. When restored, the windows are slid along for the time that passed since
+// the current window started.
+func (w *SyncSlidingWindow) MarshalBinary() ([]byte, error) {
+	return newSlidingWindowState(w.prev, w.curr).MarshalBinary()
+}
+
+func (w *SyncSlidingWindow) UnmarshalBinary(data []byte) error {
+	var s slidingWindowState
+	if err := s.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	w.restore(s)
+	return nil
+}
+
+func (w *SyncSlidingWindow) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newSlidingWindowState(w.prev, w.curr))
+}
+
+func (w *SyncSlidingWindow) UnmarshalJSON(data []byte) error {
+	var s slidingWindowState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	w.restore(s)
+	return nil
+}
+
+func (w *SyncSlidingWindow) restore(s slidingWindowState) {
+	s.restore(w.prev, w.curr)
+	w.adjustWindows(now())
 }
 
 func (w *SyncSlidingWindow) adjustWindows(t time.Time) {
@@ -2,6 +2,7 @@ package strategy
 
 import (
 	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -112,6 +113,60 @@ func TestSlidingWindow(t *testing.T) {
 	}
 }
 
+func TestSlidingWindow_Stop(t *testing.T) {
+	sl := NewSlidingWindow(1*time.Millisecond, 10)
+
+	sl.Stop()
+
+	select {
+	case <-sl.done:
+	default:
+		t.Fatal("the goroutine sliding the windows is still running after Stop")
+	}
+	success, err := sl.AddN(1)
+	assert.False(t, success)
+	assert.Error(t, err)
+
+	// stopping again returns straight away
+	sl.Stop()
+}
+
+func TestSlidingWindow_SlidesByClock(t *testing.T) {
+	// the clock is only set while the window is not running, and moved with an atomic so its goroutine can read it
+	var elapsed atomic.Int64
+	restoreClock := SetClock(func() time.Time {
+		return fakeNow.Add(time.Duration(elapsed.Load()))
+	})
+	defer restoreClock()
+
+	sl := NewSlidingWindow(1*time.Hour, 10)
+	defer sl.Stop()
+	require.True(t, sl.Take(10).Allowed)
+
+	t.Run("slides once the clock reaches the end of the window", func(t *testing.T) {
+		elapsed.Store(int64(1 * time.Hour))
+
+		gotPrevCount, gotCurrCount := sl.GetCount()
+		assert.Equal(t, 10, gotPrevCount)
+		assert.Equal(t, 0, gotCurrCount)
+	})
+
+	t.Run("the previous window is weighted by the clock", func(t *testing.T) {
+		elapsed.Store(int64(90 * time.Minute))
+
+		assert.False(t, sl.Take(6).Allowed)
+		assert.True(t, sl.Take(5).Allowed)
+	})
+
+	t.Run("both windows are emptied after two intervals", func(t *testing.T) {
+		elapsed.Store(int64(4 * time.Hour))
+
+		gotPrevCount, gotCurrCount := sl.GetCount()
+		assert.Equal(t, 0, gotPrevCount)
+		assert.Equal(t, 0, gotCurrCount)
+	})
+}
+
 func TestSyncSlidingWindow(t *testing.T) {
 	capacity := 10
 	interval := 1 * time.Minute
@@ -226,3 +281,53 @@ func TestSyncSlidingWindow(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncSlidingWindow_Peek(t *testing.T) {
+	capacity := 10
+	interval := 1 * time.Minute
+	testCases := []struct {
+		desc            string
+		firstAddN       int
+		peekN           int
+		fakeTimeElapsed time.Duration
+		wantResult      Result
+	}{
+		{
+			desc:            "peek within capacity is allowed",
+			firstAddN:       10,
+			peekN:           4,
+			fakeTimeElapsed: 30 * time.Second,
+			wantResult:      Result{Allowed: true, Limit: 10, Remaining: 1, ResetAfter: 90 * time.Second},
+		},
+		{
+			desc:            "peek over capacity waits until the count has slid down enough",
+			firstAddN:       10,
+			peekN:           6,
+			fakeTimeElapsed: 30 * time.Second,
+			wantResult:      Result{Allowed: false, Limit: 10, Remaining: 5, RetryAfter: 66 * time.Second, ResetAfter: 90 * time.Second},
+		},
+		{
+			desc:            "peek over capacity in the next window waits within that window",
+			firstAddN:       10,
+			peekN:           5,
+			fakeTimeElapsed: 70 * time.Second,
+			wantResult:      Result{Allowed: false, Limit: 10, Remaining: 0, RetryAfter: 30 * time.Second, ResetAfter: 60 * time.Second},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			teardown := fakeTimeSetup(t)
+			defer teardown()
+
+			sl := NewSyncSlidingWindow(interval, capacity)
+			_, gotErr := sl.AddN(tC.firstAddN)
+			require.NoError(t, gotErr)
+
+			setFakeNow(fakeNow.Add(tC.fakeTimeElapsed))
+
+			assert.Equal(t, tC.wantResult, sl.Peek(tC.peekN))
+			gotPrevCount, gotCurrCount := sl.Count()
+			assert.Equal(t, tC.firstAddN, gotPrevCount+gotCurrCount)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package strategy
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// snapshotVersion is the first byte of the binary encodings, so that the encodings can change later.
+const snapshotVersion byte = 1
+
+// The snapshots only hold the state of a limiter and not its configuration, which comes from the limiter it is
+// restored into. Times are kept as they were, so the time a limiter was not in use is accounted for when it is
+// restored, the same as if it had been idle for that long.
+
+type leakyBucketState struct {
+	Current     int       `json:"current"`
+	LastUpdated time.Time `json:"lastUpdated"`
+}
+
+func (s leakyBucketState) MarshalBinary() ([]byte, error) {
+	return encodeSnapshot(int64(s.Current), s.LastUpdated.UnixNano()), nil
+}
+
+func (s *leakyBucketState) UnmarshalBinary(data []byte) error {
+	fields, err := decodeSnapshot(data, 2)
+	if err != nil {
+		return err
+	}
+	s.Current = int(fields[0])
+	s.LastUpdated = time.Unix(0, fields[1])
+	return nil
+}
+
+type slidingWindowState struct {
+	PrevStart time.Time `json:"prevStart"`
+	PrevCount int       `json:"prevCount"`
+	CurrStart time.Time `json:"currStart"`
+	CurrCount int       `json:"currCount"`
+}
+
+func newSlidingWindowState(prev *window, curr *window) slidingWindowState {
+	return slidingWindowState{
+		PrevStart: prev.StartTime(),
+		PrevCount: prev.Count(),
+		CurrStart: curr.StartTime(),
+		CurrCount: curr.Count(),
+	}
+}
+
+func (s slidingWindowState) restore(prev *window, curr *window) {
+	prev.Set(s.PrevStart, s.PrevCount)
+	curr.Set(s.CurrStart, s.CurrCount)
+}
+
+func (s slidingWindowState) MarshalBinary() ([]byte, error) {
+	return encodeSnapshot(s.PrevStart.UnixNano(), int64(s.PrevCount), s.CurrStart.UnixNano(), int64(s.CurrCount)), nil
+}
+
+func (s *slidingWindowState) UnmarshalBinary(data []byte) error {
+	fields, err := decodeSnapshot(data, 4)
+	if err != nil {
+		return err
+	}
+	s.PrevStart = time.Unix(0, fields[0])
+	s.PrevCount = int(fields[1])
+	s.CurrStart = time.Unix(0, fields[2])
+	s.CurrCount = int(fields[3])
+	return nil
+}
+
+func encodeSnapshot(fields ...int64) []byte {
+	data := make([]byte, 1+8*len(fields))
+	data[0] = snapshotVersion
+	for i, f := range fields {
+		binary.BigEndian.PutUint64(data[1+8*i:], uint64(f))
+	}
+	return data
+}
+
+func decodeSnapshot(data []byte, n int) ([]int64, error) {
+	if len(data) == 0 || data[0] != snapshotVersion {
+		return nil, errors.New("unsupported snapshot version")
+	}
+	if len(data) != 1+8*n {
+		return nil, errors.New("invalid snapshot length")
+	}
+
+	fields := make([]int64, n)
+	for i := range fields {
+		fields[i] = int64(binary.BigEndian.Uint64(data[1+8*i:]))
+	}
+	return fields, nil
+}
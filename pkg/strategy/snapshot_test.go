@@ -0,0 +1,253 @@
+package strategy
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type snapshotCodec struct {
+	desc      string
+	marshal   func(v interface{}) ([]byte, error)
+	unmarshal func(data []byte, v interface{}) error
+}
+
+var snapshotCodecs = []snapshotCodec{
+	{
+		desc: "binary",
+		marshal: func(v interface{}) ([]byte, error) {
+			return v.(encoding.BinaryMarshaler).MarshalBinary()
+		},
+		unmarshal: func(data []byte, v interface{}) error {
+			return v.(encoding.BinaryUnmarshaler).UnmarshalBinary(data)
+		},
+	},
+	{
+		desc:      "json",
+		marshal:   json.Marshal,
+		unmarshal: json.Unmarshal,
+	},
+}
+
+func TestLeakyBucket_Snapshot(t *testing.T) {
+	for _, codec := range snapshotCodecs {
+		t.Run(codec.desc, func(t *testing.T) {
+			teardown := fakeTimeSetup(t)
+			defer teardown()
+
+			bucket := NewLeakyBucket(1, 1*time.Second, 10)
+			bucket.Take(8)
+			data, err := codec.marshal(bucket)
+			require.NoError(t, err)
+
+			// 3 seconds of downtime leaks 3 out of the bucket
+			setFakeNow(fakeNow.Add(3 * time.Second))
+			restored := NewLeakyBucket(1, 1*time.Second, 10)
+			require.NoError(t, codec.unmarshal(data, restored))
+
+			assert.Equal(t, 5, restored.Count())
+		})
+	}
+}
+
+func TestSyncSlidingWindow_Snapshot(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		downtime      time.Duration
+		wantPrevCount int
+		wantCurrCount int
+	}{
+		{
+			desc:          "restoring within the window keeps the current window",
+			downtime:      30 * time.Second,
+			wantPrevCount: 0,
+			wantCurrCount: 4,
+		},
+		{
+			desc:          "restoring in the next window slides the current window into the previous",
+			downtime:      90 * time.Second,
+			wantPrevCount: 4,
+			wantCurrCount: 0,
+		},
+		{
+			desc:          "restoring after two windows starts over",
+			downtime:      3 * time.Minute,
+			wantPrevCount: 0,
+			wantCurrCount: 0,
+		},
+	}
+	for _, codec := range snapshotCodecs {
+		for _, tC := range testCases {
+			t.Run(codec.desc+" "+tC.desc, func(t *testing.T) {
+				teardown := fakeTimeSetup(t)
+				defer teardown()
+
+				w := NewSyncSlidingWindow(1*time.Minute, 10)
+				w.Take(4)
+				data, err := codec.marshal(w)
+				require.NoError(t, err)
+
+				setFakeNow(fakeNow.Add(tC.downtime))
+				restored := NewSyncSlidingWindow(1*time.Minute, 10)
+				require.NoError(t, codec.unmarshal(data, restored))
+
+				gotPrevCount, gotCurrCount := restored.Count()
+				assert.Equal(t, tC.wantPrevCount, gotPrevCount)
+				assert.Equal(t, tC.wantCurrCount, gotCurrCount)
+			})
+		}
+	}
+}
+
+func TestSlidingWindow_Snapshot(t *testing.T) {
+	for _, codec := range snapshotCodecs {
+		t.Run(codec.desc, func(t *testing.T) {
+			w := NewSlidingWindow(1*time.Hour, 10)
+			defer w.Stop()
+			w.Take(4)
+			data, err := codec.marshal(w)
+			require.NoError(t, err)
+
+			restored := NewSlidingWindow(1*time.Hour, 10)
+			defer restored.Stop()
+			require.NoError(t, codec.unmarshal(data, restored))
+
+			gotPrevCount, gotCurrCount := restored.GetCount()
+			assert.Equal(t, 0, gotPrevCount)
+			assert.Equal(t, 4, gotCurrCount)
+		})
+	}
+}
+
+func TestSlidingWindow_SnapshotSlides(t *testing.T) {
+	// the clock is only set while no window is running, and moved with an atomic so the goroutines can read it
+	var elapsed atomic.Int64
+	restoreClock := SetClock(func() time.Time {
+		return fakeNow.Add(time.Duration(elapsed.Load()))
+	})
+	defer restoreClock()
+
+	w := NewSlidingWindow(1*time.Hour, 10)
+	w.Take(4)
+	data, err := w.MarshalBinary()
+	require.NoError(t, err)
+	w.Stop()
+
+	t.Run("restored within the saved window slides at the end of the saved window", func(t *testing.T) {
+		elapsed.Store(int64(20 * time.Minute))
+		restored := NewSlidingWindow(1*time.Hour, 10)
+		defer restored.Stop()
+		require.NoError(t, restored.UnmarshalBinary(data))
+
+		restored.Lock()
+		gotNextSlide := restored.nextSlide(now())
+		restored.Unlock()
+		assert.Equal(t, 40*time.Minute, gotNextSlide)
+		gotPrevCount, gotCurrCount := restored.GetCount()
+		assert.Equal(t, 0, gotPrevCount)
+		assert.Equal(t, 4, gotCurrCount)
+	})
+
+	t.Run("restored after the saved window has ended is slid on restore", func(t *testing.T) {
+		elapsed.Store(int64(70 * time.Minute))
+		restored := NewSlidingWindow(1*time.Hour, 10)
+		defer restored.Stop()
+		require.NoError(t, restored.UnmarshalBinary(data))
+
+		gotPrevCount, gotCurrCount := restored.GetCount()
+		assert.Equal(t, 4, gotPrevCount)
+		assert.Equal(t, 0, gotCurrCount)
+	})
+}
+
+func TestKeyedLimiter_Snapshot(t *testing.T) {
+	newLimiter := func() Limiter {
+		return NewTokenBucketLimiter(datastruct.NewTokenBucket(5, 0))
+	}
+
+	for _, codec := range snapshotCodecs {
+		t.Run(codec.desc, func(t *testing.T) {
+			teardown := fakeTimeSetup(t)
+			defer teardown()
+
+			limiter := NewKeyedLimiter[string](newLimiter, 1*time.Minute)
+			limiter.Take("a", 3)
+			limiter.Take("b", 1)
+			data, err := codec.marshal(limiter)
+			require.NoError(t, err)
+
+			restored := NewKeyedLimiter[string](newLimiter, 1*time.Minute)
+			restored.Take("c", 1)
+			require.NoError(t, codec.unmarshal(data, restored))
+
+			assert.Equal(t, 2, restored.Len())
+			assert.Equal(t, 2, restored.Peek("a", 1).Remaining)
+			assert.Equal(t, 4, restored.Peek("b", 1).Remaining)
+			assert.Equal(t, 5, restored.Peek("c", 1).Remaining)
+		})
+	}
+}
+
+func TestKeyedLimiter_SnapshotUnsupported(t *testing.T) {
+	limiter := NewKeyedLimiter[string](func() Limiter {
+		return NewFixedWindow(1*time.Second, 0, 1)
+	}, 1*time.Minute)
+	limiter.Take("a", 1)
+
+	_, err := limiter.MarshalBinary()
+
+	assert.EqualError(t, err, "limiter for key a cannot be marshalled")
+}
+
+func TestKeyedLimiter_SnapshotRestoreFails(t *testing.T) {
+	limiter := NewKeyedLimiter[string](func() Limiter {
+		return NewTokenBucketLimiter(datastruct.NewTokenBucket(5, 0))
+	}, 1*time.Minute)
+	limiter.Take("a", 1)
+	limiter.Take("b", 1)
+	data, err := limiter.MarshalJSON()
+	require.NoError(t, err)
+
+	var created []*stoppableLimiter
+	restored := NewKeyedLimiter[string](func() Limiter {
+		l := &stoppableLimiter{FixedWindow: NewFixedWindow(1*time.Second, 0, 1)}
+		created = append(created, l)
+		return l
+	}, 1*time.Minute)
+	restored.Take("c", 1)
+
+	err = restored.UnmarshalJSON(data)
+
+	assert.Error(t, err)
+	// the limiters created for the snapshot are stopped, and the existing key is kept running
+	require.Len(t, created, 2)
+	assert.False(t, created[0].stopped)
+	assert.True(t, created[1].stopped)
+	assert.Equal(t, 1, restored.Len())
+}
+
+func TestDecodeSnapshot(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		data    []byte
+		wantErr error
+	}{
+		{desc: "empty", data: nil, wantErr: errors.New("unsupported snapshot version")},
+		{desc: "unknown version", data: []byte{2, 0}, wantErr: errors.New("unsupported snapshot version")},
+		{desc: "truncated", data: []byte{1, 0, 0}, wantErr: errors.New("invalid snapshot length")},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			_, err := decodeSnapshot(tC.data, 2)
+
+			assert.Equal(t, tC.wantErr, err)
+		})
+	}
+}
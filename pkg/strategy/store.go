@@ -0,0 +1,69 @@
+package strategy
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+)
+
+// Store keeps limiter state outside of the limiter, so that several replicas can share the same limit.
+type Store interface {
+	// Get returns the value of key, or nil if it does not exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// CompareAndSwap sets key to new, expiring after ttl, only if its value is still old. A nil old value means
+	// the key must not exist, and a ttl of 0 means the key does not expire.
+	CompareAndSwap(ctx context.Context, key string, old []byte, new []byte, ttl time.Duration) (bool, error)
+}
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryStore is a Store for limiters within a single process.
+type MemoryStore struct {
+	sync.Mutex
+	entries map[string]memoryEntry
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.get(key), nil
+}
+
+func (s *MemoryStore) CompareAndSwap(ctx context.Context, key string, old []byte, new []byte, ttl time.Duration) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	curr := s.get(key)
+	if (curr == nil) != (old == nil) || !bytes.Equal(curr, old) {
+		return false, nil
+	}
+	e := memoryEntry{value: new}
+	if ttl > 0 {
+		e.expiresAt = now().Add(ttl)
+	}
+	s.entries[key] = e
+	return true, nil
+}
+
+func (s *MemoryStore) get(key string) []byte {
+	e, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	if !e.expiresAt.IsZero() && !now().Before(e.expiresAt) {
+		delete(s.entries, key)
+		return nil
+	}
+	return e.value
+}
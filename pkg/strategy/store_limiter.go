@@ -0,0 +1,219 @@
+package strategy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+)
+
+// maxStoreAttempts is how many times a take is tried when another replica keeps updating the same key in
+// between reading and writing it.
+const maxStoreAttempts = 10
+
+// storeAlgorithm runs a limiter against state loaded from a Store.
+type storeAlgorithm interface {
+	// apply returns the result of taking n from state, or only peeking if take is false, along with the new
+	// state. A nil state is a limit that has not been used yet.
+	apply(state []byte, n int, take bool) ([]byte, Result, error)
+	// ttl returns how long the state written after res has to be kept, or 0 if the limit never resets and the
+	// state must not expire.
+	ttl(res Result) time.Duration
+}
+
+// StoreLimiter runs the same algorithms as the in-memory limiters, but keeps the state per key in a Store,
+// so that every replica using the same store shares the limit.
+type StoreLimiter struct {
+	store     Store
+	prefix    string
+	algorithm storeAlgorithm
+}
+
+// NewStoreTokenBucket limits like datastruct.TokenBucket. Keys are stored with the given prefix.
+func NewStoreTokenBucket(store Store, prefix string, maxTokens float64, refillRatePerSecond float64) *StoreLimiter {
+	return &StoreLimiter{
+		store:  store,
+		prefix: prefix,
+		algorithm: &tokenBucketAlgorithm{
+			maxTokens:           maxTokens,
+			refillRatePerSecond: refillRatePerSecond,
+		},
+	}
+}
+
+// NewStoreLeakyBucket limits like LeakyBucket.Take. Keys are stored with the given prefix.
+func NewStoreLeakyBucket(store Store, prefix string, tokensPerInterval int, interval time.Duration, capacity int) *StoreLimiter {
+	return &StoreLimiter{
+		store:  store,
+		prefix: prefix,
+		algorithm: &leakyBucketAlgorithm{
+			tokensPerInterval: tokensPerInterval,
+			interval:          interval,
+			capacity:          capacity,
+		},
+	}
+}
+
+// NewStoreSlidingWindow limits like SyncSlidingWindow. Keys are stored with the given prefix.
+func NewStoreSlidingWindow(store Store, prefix string, interval time.Duration, capacity int) *StoreLimiter {
+	return &StoreLimiter{
+		store:  store,
+		prefix: prefix,
+		algorithm: &slidingWindowAlgorithm{
+			interval: interval,
+			capacity: capacity,
+		},
+	}
+}
+
+func (l *StoreLimiter) Peek(ctx context.Context, key string, n int) (Result, error) {
+	state, err := l.store.Get(ctx, l.prefix+key)
+	if err != nil {
+		return Result{}, err
+	}
+
+	_, res, err := l.algorithm.apply(state, n, false)
+	return res, err
+}
+
+// Take takes n from the limit of key. The state is written back even if n is not allowed if it changed, as the
+// sliding window moves its windows along while checking.
+func (l *StoreLimiter) Take(ctx context.Context, key string, n int) (Result, error) {
+	for attempt := 0; attempt < maxStoreAttempts; attempt++ {
+		state, err := l.store.Get(ctx, l.prefix+key)
+		if err != nil {
+			return Result{}, err
+		}
+
+		newState, res, err := l.algorithm.apply(state, n, true)
+		if err != nil || bytes.Equal(state, newState) {
+			return res, err
+		}
+
+		swapped, err := l.store.CompareAndSwap(ctx, l.prefix+key, state, newState, l.algorithm.ttl(res))
+		if err != nil {
+			return Result{}, err
+		}
+		if swapped {
+			return res, nil
+		}
+
+		// back off for a random time that grows with each attempt, so that replicas stop colliding
+		backoff := time.Duration(rand.Int63n(int64(attempt+1) * int64(time.Millisecond)))
+		select {
+		case <-ctx.Done():
+			return Result{}, ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+	return Result{}, errors.New("too many concurrent updates to the store")
+}
+
+type tokenBucketAlgorithm struct {
+	maxTokens           float64
+	refillRatePerSecond float64
+}
+
+func (a *tokenBucketAlgorithm) apply(state []byte, n int, take bool) ([]byte, Result, error) {
+	bucket := datastruct.NewTokenBucket(a.maxTokens, a.refillRatePerSecond)
+	if state != nil {
+		if err := bucket.UnmarshalJSON(state); err != nil {
+			return nil, Result{}, err
+		}
+	}
+
+	limiter := NewTokenBucketLimiter(bucket)
+	var res Result
+	if take {
+		res = limiter.Take(n)
+	} else {
+		res = limiter.Peek(n)
+	}
+
+	// a rejected take only refills, which can be worked out again from the old state
+	if !res.Allowed {
+		return state, res, nil
+	}
+
+	newState, err := bucket.MarshalJSON()
+	return newState, res, err
+}
+
+// ttl keeps the state until the bucket has refilled. Without a refill rate it never does, so it is kept for good.
+func (a *tokenBucketAlgorithm) ttl(res Result) time.Duration {
+	if a.refillRatePerSecond <= 0 {
+		return 0
+	}
+	return resetTTL(res)
+}
+
+type leakyBucketAlgorithm struct {
+	tokensPerInterval int
+	interval          time.Duration
+	capacity          int
+}
+
+func (a *leakyBucketAlgorithm) apply(state []byte, n int, take bool) ([]byte, Result, error) {
+	bucket := NewLeakyBucket(a.tokensPerInterval, a.interval, a.capacity)
+	if state != nil {
+		if err := bucket.UnmarshalJSON(state); err != nil {
+			return nil, Result{}, err
+		}
+	}
+
+	var res Result
+	if take {
+		res = bucket.Take(n)
+	} else {
+		res = bucket.Peek(n)
+	}
+
+	// a rejected take does not change the bucket
+	if !res.Allowed {
+		return state, res, nil
+	}
+
+	newState, err := bucket.MarshalJSON()
+	return newState, res, err
+}
+
+func (a *leakyBucketAlgorithm) ttl(res Result) time.Duration {
+	return resetTTL(res)
+}
+
+type slidingWindowAlgorithm struct {
+	interval time.Duration
+	capacity int
+}
+
+func (a *slidingWindowAlgorithm) apply(state []byte, n int, take bool) ([]byte, Result, error) {
+	w := NewSyncSlidingWindow(a.interval, a.capacity)
+	if state != nil {
+		if err := w.UnmarshalJSON(state); err != nil {
+			return nil, Result{}, err
+		}
+	}
+
+	var res Result
+	if take {
+		res = w.Take(n)
+	} else {
+		res = w.Peek(n)
+	}
+
+	newState, err := w.MarshalJSON()
+	return newState, res, err
+}
+
+func (a *slidingWindowAlgorithm) ttl(res Result) time.Duration {
+	return resetTTL(res)
+}
+
+// resetTTL keeps the state until the limit has fully reset, as from then on it is no different to a key that was
+// never used.
+func resetTTL(res Result) time.Duration {
+	return res.ResetAfter + time.Second
+}
@@ -0,0 +1,135 @@
+package strategy
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreLimiter_SharedBudget(t *testing.T) {
+	store := NewMemoryStore()
+	testCases := []struct {
+		desc       string
+		newLimiter func() *StoreLimiter
+	}{
+		{
+			desc: "token bucket",
+			newLimiter: func() *StoreLimiter {
+				return NewStoreTokenBucket(store, "tb:", 20, 0)
+			},
+		},
+		{
+			desc: "leaky bucket",
+			newLimiter: func() *StoreLimiter {
+				return NewStoreLeakyBucket(store, "lb:", 1, 1*time.Minute, 20)
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			teardown := fakeTimeSetup(t)
+			defer teardown()
+
+			// each replica has its own limiter, but they all share the store
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			allowed := 0
+			for replica := 0; replica < 4; replica++ {
+				wg.Add(1)
+				limiter := tC.newLimiter()
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 10; i++ {
+						res, err := limiter.Take(context.Background(), "tenant", 1)
+						assert.NoError(t, err)
+						if res.Allowed {
+							mu.Lock()
+							allowed++
+							mu.Unlock()
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			assert.Equal(t, 20, allowed)
+		})
+	}
+}
+
+func TestStoreLimiter_SlidingWindow(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	ctx := context.Background()
+	limiter := NewStoreSlidingWindow(NewMemoryStore(), "", 1*time.Minute, 10)
+	inMemory := NewSyncSlidingWindow(1*time.Minute, 10)
+
+	steps := []struct {
+		fakeTimeElapsed time.Duration
+		n               int
+	}{
+		{fakeTimeElapsed: 0, n: 10},
+		{fakeTimeElapsed: 30 * time.Second, n: 6},
+		{fakeTimeElapsed: 30 * time.Second, n: 4},
+		{fakeTimeElapsed: 70 * time.Second, n: 5},
+		{fakeTimeElapsed: 100 * time.Second, n: 5},
+		{fakeTimeElapsed: 5 * time.Minute, n: 10},
+	}
+	for _, step := range steps {
+		setFakeNow(fakeNow.Add(step.fakeTimeElapsed))
+
+		peeked, err := limiter.Peek(ctx, "k", step.n)
+		require.NoError(t, err)
+		got, err := limiter.Take(ctx, "k", step.n)
+		require.NoError(t, err)
+
+		want := inMemory.Take(step.n)
+		assert.Equal(t, want, got)
+		assert.Equal(t, want, peeked)
+	}
+}
+
+func TestStoreLimiter_KeysAreIndependent(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	ctx := context.Background()
+	limiter := NewStoreLeakyBucket(NewMemoryStore(), "", 10, 1*time.Minute, 5)
+
+	res, err := limiter.Take(ctx, "a", 5)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	res, err = limiter.Take(ctx, "a", 1)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 6*time.Second, res.RetryAfter)
+
+	res, err = limiter.Take(ctx, "b", 5)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+}
+
+func TestStoreLimiter_TokenBucketWithoutRefill(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	ctx := context.Background()
+	limiter := NewStoreTokenBucket(NewMemoryStore(), "", 5, 0)
+
+	res, err := limiter.Take(ctx, "k", 5)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	// the state must not expire, otherwise the budget would come back in full
+	setFakeNow(fakeNow.Add(24 * time.Hour))
+
+	res, err = limiter.Take(ctx, "k", 1)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+}
@@ -0,0 +1,64 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	teardown := fakeTimeSetup(t)
+	defer teardown()
+
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	t.Run("swap from nil creates the key", func(t *testing.T) {
+		swapped, err := store.CompareAndSwap(ctx, "k", nil, []byte("1"), 1*time.Minute)
+		require.NoError(t, err)
+		assert.True(t, swapped)
+
+		got, err := store.Get(ctx, "k")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("1"), got)
+	})
+
+	t.Run("swap with a stale value fails", func(t *testing.T) {
+		swapped, err := store.CompareAndSwap(ctx, "k", nil, []byte("2"), 1*time.Minute)
+		require.NoError(t, err)
+		assert.False(t, swapped)
+
+		swapped, err = store.CompareAndSwap(ctx, "k", []byte("0"), []byte("2"), 1*time.Minute)
+		require.NoError(t, err)
+		assert.False(t, swapped)
+	})
+
+	t.Run("swap with the current value works", func(t *testing.T) {
+		swapped, err := store.CompareAndSwap(ctx, "k", []byte("1"), []byte("2"), 1*time.Minute)
+		require.NoError(t, err)
+		assert.True(t, swapped)
+	})
+
+	t.Run("expired keys do not exist", func(t *testing.T) {
+		setFakeNow(fakeNow.Add(1 * time.Minute))
+
+		got, err := store.Get(ctx, "k")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("keys without a ttl do not expire", func(t *testing.T) {
+		swapped, err := store.CompareAndSwap(ctx, "forever", nil, []byte("1"), 0)
+		require.NoError(t, err)
+		require.True(t, swapped)
+
+		setFakeNow(fakeNow.Add(24 * time.Hour))
+
+		got, err := store.Get(ctx, "forever")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("1"), got)
+	})
+}
@@ -0,0 +1,87 @@
+package strategy
+
+import (
+	"math"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+)
+
+// TokenBucketLimiter adapts a datastruct.TokenBucket to the Limiter interface.
+type TokenBucketLimiter struct {
+	bucket *datastruct.TokenBucket
+}
+
+func NewTokenBucketLimiter(bucket *datastruct.TokenBucket) *TokenBucketLimiter {
+	return &TokenBucketLimiter{
+		bucket: bucket,
+	}
+}
+
+func (l *TokenBucketLimiter) Bucket() *datastruct.TokenBucket {
+	return l.bucket
+}
+
+func (l *TokenBucketLimiter) Peek(n int) Result {
+	tokens := l.bucket.Tokens()
+	return l.result(tokens >= float64(n), tokens, n)
+}
+
+func (l *TokenBucketLimiter) Take(n int) Result {
+	err := l.bucket.TakeN(n)
+	return l.result(err == nil, l.bucket.Tokens(), n)
+}
+
+// ReserveN takes n like Take and returns a reservation that puts the tokens back into the bucket.
+func (l *TokenBucketLimiter) ReserveN(n int) (*Reservation, bool) {
+	_, r := l.Reserve(n)
+	return r, r != nil
+}
+
+func (l *TokenBucketLimiter) Reserve(n int) (Result, *Reservation) {
+	res := l.Take(n)
+	if !res.Allowed {
+		return res, nil
+	}
+	return res, newReservation(n, l.bucket.Refund)
+}
+
+// result builds the result from the tokens left after n was taken, or before if n was not allowed.
+func (l *TokenBucketLimiter) result(allowed bool, tokens float64, n int) Result {
+	maxTokens := l.bucket.MaxTokens()
+	res := Result{
+		Allowed:    allowed,
+		Limit:      int(maxTokens),
+		Remaining:  int(tokens),
+		ResetAfter: l.refillDuration(maxTokens - tokens),
+	}
+	if !allowed {
+		res.RetryAfter = l.refillDuration(float64(n) - tokens)
+	}
+	return res
+}
+
+// refillDuration returns how long it takes to refill n tokens.
+func (l *TokenBucketLimiter) refillDuration(n float64) time.Duration {
+	rate := l.bucket.RefillRatePerSecond()
+	if n <= 0 || rate == 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(n / rate * float64(time.Second)))
+}
+
+func (l *TokenBucketLimiter) MarshalBinary() ([]byte, error) {
+	return l.bucket.MarshalBinary()
+}
+
+func (l *TokenBucketLimiter) UnmarshalBinary(data []byte) error {
+	return l.bucket.UnmarshalBinary(data)
+}
+
+func (l *TokenBucketLimiter) MarshalJSON() ([]byte, error) {
+	return l.bucket.MarshalJSON()
+}
+
+func (l *TokenBucketLimiter) UnmarshalJSON(data []byte) error {
+	return l.bucket.UnmarshalJSON(data)
+}
@@ -0,0 +1,32 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edfoh/data-structures/pkg/datastruct"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucketLimiter(t *testing.T) {
+	limiter := NewTokenBucketLimiter(datastruct.NewTokenBucket(10, 1))
+
+	t.Run("take within the tokens is allowed", func(t *testing.T) {
+		res := limiter.Take(4)
+
+		assert.Equal(t, Result{Allowed: true, Limit: 10, Remaining: 6, ResetAfter: 4 * time.Second}, res)
+	})
+
+	t.Run("take over the tokens is rejected with how long to refill the difference", func(t *testing.T) {
+		res := limiter.Take(7)
+
+		assert.Equal(t, Result{Allowed: false, Limit: 10, Remaining: 6, RetryAfter: 1 * time.Second, ResetAfter: 4 * time.Second}, res)
+	})
+
+	t.Run("peek does not take tokens", func(t *testing.T) {
+		res := limiter.Peek(6)
+
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 6.0, limiter.Bucket().Tokens())
+	})
+}